@@ -0,0 +1,286 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerState 熔断器状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 关闭, 正常放行
+	BreakerOpen                         // 打开, 拒绝所有请求
+	BreakerHalfOpen                     // 半开, 放行少量探测请求
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen 熔断器打开时请求被拒绝返回的错误
+var ErrCircuitOpen = errors.New("熔断器已打开")
+
+// BreakerConfig 熔断器配置, 按请求的 host 分别统计
+type BreakerConfig struct {
+	ConsecutiveFailures int           `mapstructure:"consecutive_failures"`   // 连续失败达到该次数时熔断, 0 表示不按连续失败熔断
+	FailureRatio        float64       `mapstructure:"failure_ratio"`          // 统计窗口内失败率达到该值时熔断, 0 表示不按失败率熔断
+	MinRequests         int           `mapstructure:"min_requests"`           // 按失败率熔断所需的最少请求数, 默认 10
+	Interval            time.Duration `mapstructure:"interval"`               // 关闭状态下的统计窗口, 默认 60s
+	OpenTimeout         time.Duration `mapstructure:"open_timeout"`           // 熔断后进入半开状态的等待时间, 默认 30s
+	HalfOpenMaxRequests int           `mapstructure:"half_open_max_requests"` // 半开状态允许的探测请求数, 全部成功后关闭熔断器, 默认 1
+
+	// IsFailure 判断请求是否失败, 默认网络错误和 5xx 视为失败
+	IsFailure func(response *http.Response, err error) bool `mapstructure:"-"`
+}
+
+// DefaultBreakerConfig 默认熔断器配置
+var DefaultBreakerConfig = BreakerConfig{
+	ConsecutiveFailures: 5,
+	FailureRatio:        0.5,
+	MinRequests:         10,
+	Interval:            60 * time.Second,
+	OpenTimeout:         30 * time.Second,
+	HalfOpenMaxRequests: 1,
+}
+
+// BreakerGroup 按 host 分组的熔断器
+type BreakerGroup struct {
+	config   BreakerConfig
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// NewBreakerGroup 创建按 host 分组的熔断器
+// @param config BreakerConfig 熔断器配置
+func NewBreakerGroup(config BreakerConfig) *BreakerGroup {
+	if config.MinRequests <= 0 {
+		config.MinRequests = 10
+	}
+	if config.Interval <= 0 {
+		config.Interval = 60 * time.Second
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = defaultIsFailure
+	}
+	return &BreakerGroup{
+		config:   config,
+		breakers: map[string]*breaker{},
+	}
+}
+
+func defaultIsFailure(response *http.Response, err error) bool {
+	return err != nil || response.StatusCode >= http.StatusInternalServerError
+}
+
+// Middleware 熔断中间件, 熔断器打开时直接返回 ErrCircuitOpen
+func (g *BreakerGroup) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			ctx := request.Context()
+			b := g.get(request.URL.Host)
+			generation, err := b.allow(ctx)
+			if err != nil {
+				closeRequestBody(request)
+				return nil, err
+			}
+			response, err := next.RoundTrip(request)
+			// 调用方取消不计入失败
+			if err != nil && ctx.Err() != nil {
+				b.cancel(generation)
+				return response, err
+			}
+			b.done(ctx, generation, !g.config.IsFailure(response, err))
+			return response, err
+		})
+	}
+}
+
+// State 获取指定 host 的熔断器状态, 未请求过的 host 为 BreakerClosed
+// @param host string 请求地址中的 host(含端口)
+func (g *BreakerGroup) State(host string) BreakerState {
+	g.mu.Lock()
+	b, ok := g.breakers[host]
+	g.mu.Unlock()
+	if !ok {
+		return BreakerClosed
+	}
+	return b.currentState()
+}
+
+// States 获取所有 host 的熔断器状态, 可用于健康检查接口
+func (g *BreakerGroup) States() map[string]BreakerState {
+	g.mu.Lock()
+	breakers := make(map[string]*breaker, len(g.breakers))
+	for host, b := range g.breakers {
+		breakers[host] = b
+	}
+	g.mu.Unlock()
+
+	states := make(map[string]BreakerState, len(breakers))
+	for host, b := range breakers {
+		states[host] = b.currentState()
+	}
+	return states
+}
+
+func (g *BreakerGroup) get(host string) *breaker {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b, ok := g.breakers[host]
+	if !ok {
+		b = &breaker{host: host, config: &g.config, windowStart: time.Now()}
+		g.breakers[host] = b
+	}
+	return b
+}
+
+type breaker struct {
+	host   string
+	config *BreakerConfig
+
+	mu               sync.Mutex
+	state            BreakerState
+	generation       uint64
+	windowStart      time.Time
+	openedAt         time.Time
+	requests         int
+	failures         int
+	consecutive      int
+	halfOpenInFlight int
+	halfOpenSuccess  int
+}
+
+func (b *breaker) currentState() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(context.Background(), time.Now())
+	return b.state
+}
+
+// refresh 处理随时间发生的状态变化: 统计窗口过期、熔断等待结束
+func (b *breaker) refresh(ctx context.Context, now time.Time) {
+	switch b.state {
+	case BreakerClosed:
+		if now.Sub(b.windowStart) >= b.config.Interval {
+			b.resetCounts(now)
+		}
+	case BreakerOpen:
+		if now.Sub(b.openedAt) >= b.config.OpenTimeout {
+			b.setState(ctx, BreakerHalfOpen, now)
+		}
+	}
+}
+
+func (b *breaker) allow(ctx context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(ctx, time.Now())
+	switch b.state {
+	case BreakerOpen:
+		return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, b.host)
+	case BreakerHalfOpen:
+		if b.halfOpenInFlight+b.halfOpenSuccess >= b.config.HalfOpenMaxRequests {
+			return 0, fmt.Errorf("%w: %s", ErrCircuitOpen, b.host)
+		}
+		b.halfOpenInFlight++
+	}
+	return b.generation, nil
+}
+
+func (b *breaker) cancel(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation == b.generation && b.state == BreakerHalfOpen {
+		b.halfOpenInFlight--
+	}
+}
+
+func (b *breaker) done(ctx context.Context, generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	now := time.Now()
+
+	switch b.state {
+	case BreakerClosed:
+		b.requests++
+		if success {
+			b.consecutive = 0
+			return
+		}
+		b.failures++
+		b.consecutive++
+		if b.shouldTrip() {
+			b.setState(ctx, BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		b.halfOpenInFlight--
+		if !success {
+			b.setState(ctx, BreakerOpen, now)
+			return
+		}
+		b.halfOpenSuccess++
+		if b.halfOpenSuccess >= b.config.HalfOpenMaxRequests {
+			b.setState(ctx, BreakerClosed, now)
+		}
+	}
+}
+
+func (b *breaker) shouldTrip() bool {
+	if b.config.ConsecutiveFailures > 0 && b.consecutive >= b.config.ConsecutiveFailures {
+		return true
+	}
+	return b.config.FailureRatio > 0 && b.requests >= b.config.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.config.FailureRatio
+}
+
+func (b *breaker) setState(ctx context.Context, state BreakerState, now time.Time) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	b.generation++
+	b.resetCounts(now)
+	if state == BreakerOpen {
+		b.openedAt = now
+	}
+
+	fields := []any{"host", b.host, "from", from.String(), "to", state.String()}
+	if state == BreakerOpen {
+		httpLogger.CWarnw(ctx, "熔断器状态变更", fields...)
+	} else {
+		httpLogger.CInfow(ctx, "熔断器状态变更", fields...)
+	}
+}
+
+func (b *breaker) resetCounts(now time.Time) {
+	b.windowStart = now
+	b.requests = 0
+	b.failures = 0
+	b.consecutive = 0
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccess = 0
+}
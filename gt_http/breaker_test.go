@@ -0,0 +1,87 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakerGroup(t *testing.T) {
+	observeLogs(t)
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	host := mustParseURL(t, server.URL).Host
+
+	group := NewBreakerGroup(BreakerConfig{
+		ConsecutiveFailures: 3,
+		OpenTimeout:         50 * time.Millisecond,
+	})
+	client := NewClient(DefaultClientConfig).Use(group.Middleware())
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(server.URL, nil)
+		assert.Equal(t, http.StatusServiceUnavailable, GetStatusCode(err))
+	}
+	assert.Equal(t, BreakerOpen, group.State(host))
+	assert.Equal(t, map[string]BreakerState{host: BreakerOpen}, group.States())
+
+	_, err := client.Get(server.URL, nil)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+
+	// 熔断时直接返回, 同样关闭请求体
+	body := &closeTracker{Reader: strings.NewReader("data")}
+	request, _ := http.NewRequest(http.MethodPut, server.URL, body)
+	_, err = group.Middleware()(http.DefaultTransport).RoundTrip(request)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.True(t, body.closed)
+
+	// 半开状态探测失败重新熔断
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, BreakerHalfOpen, group.State(host))
+	_, err = client.Get(server.URL, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, GetStatusCode(err))
+	assert.Equal(t, BreakerOpen, group.State(host))
+
+	// 半开状态探测成功关闭熔断器
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	_, err = client.Get(server.URL, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, group.State(host))
+	assert.Equal(t, BreakerClosed, group.State("unknown:80"))
+}
+
+func TestBreaker_FailureRatio(t *testing.T) {
+	b := &breaker{
+		host:        "h",
+		config:      &BreakerConfig{FailureRatio: 0.5, MinRequests: 4, Interval: time.Minute, OpenTimeout: time.Minute},
+		windowStart: time.Now(),
+	}
+	ctx := context.Background()
+	results := []bool{true, false, true, false}
+	for _, success := range results {
+		generation, err := b.allow(ctx)
+		assert.NoError(t, err)
+		b.done(ctx, generation, success)
+	}
+	assert.Equal(t, BreakerOpen, b.currentState())
+}
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+	return u
+}
@@ -0,0 +1,367 @@
+package gt_http
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStatusHeader 缓存中间件在响应中写入的缓存状态请求头, 值为 hit / revalidated / miss
+const CacheStatusHeader = "X-Gt-Cache"
+
+// CacheConfig 响应缓存配置
+type CacheConfig struct {
+	MaxEntries int           `mapstructure:"max_entries"` // 最大缓存条数, 默认 1000
+	MaxBytes   int64         `mapstructure:"max_bytes"`   // 最大缓存字节数(响应体), 默认 64MB
+	DefaultTTL time.Duration `mapstructure:"default_ttl"` // 响应没有 Cache-Control / Expires 时的缓存时间, 0 表示不缓存这类响应(包括带 ETag 的)
+}
+
+type cacheBypassCtxKey struct{}
+
+// WithCacheBypass 跳过缓存直接请求, 结果仍会写入缓存
+// @param ctx context.Context 上下文
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassCtxKey{}, true)
+}
+
+// Cache 内存响应缓存, 遵循 Cache-Control max-age / no-cache / no-store / public、Expires 和 Vary,
+// 过期后使用 ETag / Last-Modified 发送条件请求, 按 LRU 淘汰;
+// no-cache 或 max-age=0 且带 ETag / Last-Modified 的响应只用于条件请求, 每次使用前都会向服务端确认
+type Cache struct {
+	config CacheConfig
+	mu     sync.Mutex
+	lru    *list.List
+	items  map[string]*list.Element
+	varies map[string]*cacheVary
+	bytes  int64
+}
+
+// cacheVary 同一请求地址最近一次响应的 Vary 请求头, 以及该地址的缓存条数
+type cacheVary struct {
+	names   []string
+	entries int
+}
+
+type cacheEntry struct {
+	key          string
+	url          string
+	vary         []string
+	status       int
+	header       http.Header
+	body         []byte
+	expireAt     time.Time
+	etag         string
+	lastModified string
+}
+
+// NewCache 创建响应缓存
+// @param config CacheConfig 缓存配置
+func NewCache(config CacheConfig) *Cache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 64 << 20
+	}
+	return &Cache{
+		config: config,
+		lru:    list.New(),
+		items:  map[string]*list.Element{},
+		varies: map[string]*cacheVary{},
+	}
+}
+
+// Middleware 缓存中间件, 仅缓存 GET 请求的 200 响应
+// 缓存键为请求地址和响应 Vary 指定的请求头; 带 Authorization 的请求仅在响应声明 Cache-Control: public 时缓存
+// 请求头 Cache-Control: no-cache 或 WithCacheBypass 跳过读取缓存, no-store 既不读取也不写入
+func (c *Cache) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			if request.Method != http.MethodGet {
+				return next.RoundTrip(request)
+			}
+			reqDirectives := parseCacheControl(request.Header.Get("Cache-Control"))
+			if _, ok := reqDirectives["no-store"]; ok {
+				return next.RoundTrip(request)
+			}
+			_, noCache := reqDirectives["no-cache"]
+			bypass, _ := request.Context().Value(cacheBypassCtxKey{}).(bool)
+
+			entry := c.get(c.key(request))
+			if entry != nil && !noCache && !bypass {
+				if time.Now().Before(entry.expireAt) {
+					return entry.response(request, "hit"), nil
+				}
+				if entry.etag != "" || entry.lastModified != "" {
+					return c.revalidate(next, request, entry)
+				}
+			}
+
+			response, err := next.RoundTrip(request)
+			if err != nil {
+				return response, err
+			}
+			return c.store(request, response)
+		})
+	}
+}
+
+// Len 获取缓存条数
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Purge 清空缓存
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.items = map[string]*list.Element{}
+	c.varies = map[string]*cacheVary{}
+	c.bytes = 0
+}
+
+func (c *Cache) revalidate(next http.RoundTripper, request *http.Request, entry *cacheEntry) (*http.Response, error) {
+	conditional := request.Clone(request.Context())
+	if entry.etag != "" {
+		conditional.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		conditional.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+	response, err := next.RoundTrip(conditional)
+	if err != nil {
+		return response, err
+	}
+	if response.StatusCode != http.StatusNotModified {
+		return c.store(request, response)
+	}
+	_ = response.Body.Close()
+
+	// 304 响应刷新缓存的有效期和响应头, 缓存项创建后不再修改, 因此写入新的缓存项
+	updated := *entry
+	updated.header = entry.header.Clone()
+	for k, v := range response.Header {
+		if k != "Content-Length" {
+			updated.header[k] = v
+		}
+	}
+	if ttl, ok := c.ttl(response.Header); ok {
+		updated.expireAt = time.Now().Add(ttl)
+	}
+	c.put(&updated)
+	return updated.response(request, "revalidated"), nil
+}
+
+// store 读取可缓存的响应并写入缓存, 返回使用已读取内容的响应
+func (c *Cache) store(request *http.Request, response *http.Response) (*http.Response, error) {
+	vary, ok := parseVary(response.Header)
+	if response.StatusCode != http.StatusOK || !ok {
+		return response, nil
+	}
+	if request.Header.Get("Authorization") != "" {
+		// 带认证的响应可能因用户而异, 除非服务端声明可共享
+		if _, public := parseCacheControl(response.Header.Get("Cache-Control"))["public"]; !public {
+			return response, nil
+		}
+	}
+	ttl, ok := c.ttl(response.Header)
+	etag := response.Header.Get("ETag")
+	lastModified := response.Header.Get("Last-Modified")
+	if !ok || (ttl <= 0 && etag == "" && lastModified == "") {
+		return response, nil
+	}
+	if response.ContentLength > c.config.MaxBytes {
+		return response, nil
+	}
+
+	// 长度未知的响应体最多读取 MaxBytes+1 字节, 超出时不缓存, 已读取的部分和剩余内容照常返回
+	body, err := io.ReadAll(io.LimitReader(response.Body, c.config.MaxBytes+1))
+	if err != nil {
+		_ = response.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > c.config.MaxBytes {
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+		return response, nil
+	}
+	_ = response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	response.Header.Set(CacheStatusHeader, "miss")
+
+	url := request.URL.String()
+	entry := &cacheEntry{
+		key:          varyKey(url, vary, request.Header),
+		url:          url,
+		vary:         vary,
+		status:       response.StatusCode,
+		header:       response.Header.Clone(),
+		body:         body,
+		expireAt:     time.Now().Add(ttl),
+		etag:         etag,
+		lastModified: lastModified,
+	}
+	c.put(entry)
+	return response, nil
+}
+
+// key 计算请求的缓存键, 使用该地址最近一次响应的 Vary 请求头
+func (c *Cache) key(request *http.Request) string {
+	url := request.URL.String()
+	c.mu.Lock()
+	vary := c.varies[url]
+	c.mu.Unlock()
+	if vary == nil {
+		return url
+	}
+	return varyKey(url, vary.names, request.Header)
+}
+
+// varyKey 缓存键为请求地址加上 names 中请求头的值
+func varyKey(url string, names []string, header http.Header) string {
+	if len(names) == 0 {
+		return url
+	}
+	var sb strings.Builder
+	sb.WriteString(url)
+	for _, name := range names {
+		sb.WriteByte('\n')
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(header.Values(name), ","))
+	}
+	return sb.String()
+}
+
+// parseVary 解析响应的 Vary 请求头名称, 返回 false 表示 Vary: * 不可缓存
+func parseVary(header http.Header) ([]string, bool) {
+	var names []string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "*" {
+				return nil, false
+			}
+			if name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+// ttl 根据响应头计算缓存时间, 返回 false 表示不可缓存
+func (c *Cache) ttl(header http.Header) (time.Duration, bool) {
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return 0, false
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return 0, true
+	}
+	if maxAge, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return 0, true
+		}
+		age, _ := strconv.Atoi(header.Get("Age"))
+		return time.Duration(seconds-age) * time.Second, true
+	}
+	if expires := header.Get("Expires"); expires != "" {
+		expireAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0, true
+		}
+		now := time.Now()
+		if date, dateErr := http.ParseTime(header.Get("Date")); dateErr == nil {
+			now = date
+		}
+		return expireAt.Sub(now), true
+	}
+	// 没有有效期信息时按 DefaultTTL 缓存, 为 0 时即使带有 ETag / Last-Modified 也不缓存
+	return c.config.DefaultTTL, c.config.DefaultTTL > 0
+}
+
+func (c *Cache) get(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry)
+}
+
+// put 写入缓存项, 并以其 Vary 请求头作为该地址后续请求的缓存键
+func (c *Cache) put(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[entry.key]; ok {
+		c.remove(e)
+	}
+	if c.varies[entry.url] == nil {
+		c.varies[entry.url] = &cacheVary{}
+	}
+	c.varies[entry.url].names = entry.vary
+	c.varies[entry.url].entries++
+	c.items[entry.key] = c.lru.PushFront(entry)
+	c.bytes += int64(len(entry.body))
+	for c.lru.Len() > c.config.MaxEntries || c.bytes > c.config.MaxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*cacheEntry)
+	delete(c.items, entry.key)
+	c.bytes -= int64(len(entry.body))
+	if vary := c.varies[entry.url]; vary != nil {
+		if vary.entries--; vary.entries <= 0 {
+			delete(c.varies, entry.url)
+		}
+	}
+}
+
+func (e *cacheEntry) response(request *http.Request, status string) *http.Response {
+	header := e.header.Clone()
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       request,
+	}
+}
+
+func parseCacheControl(value string) map[string]string {
+	directives := map[string]string{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(part, "=")
+		directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(arg), `"`)
+	}
+	return directives
+}
@@ -0,0 +1,206 @@
+package gt_http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var calls, notModified int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch r.URL.Path {
+		case "/max-age":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/last-modified":
+			w.Header().Set("Cache-Control", "max-age=0")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			if r.Header.Get("If-Modified-Since") != "" {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/etag-only":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+		case "/vary":
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Language")
+		case "/public":
+			w.Header().Set("Cache-Control", "public, max-age=60")
+		case "/chunked":
+			// 未知长度的响应体, 前 48 字节发送后等待客户端通知再发送剩余部分
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte(strings.Repeat("x", 48)))
+			w.(http.Flusher).Flush()
+			if r.URL.Query().Has("wait") {
+				<-release
+			}
+			_, _ = w.Write([]byte(strings.Repeat("x", 16)))
+			return
+		}
+		_, _ = w.Write([]byte(strconv.Itoa(int(n))))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	newCachedClient := func(config CacheConfig) (*Client, *Cache) {
+		cache := NewCache(config)
+		return NewClient(DefaultClientConfig).Use(cache.Middleware()), cache
+	}
+
+	t.Run("max-age", func(t *testing.T) {
+		client, _ := newCachedClient(CacheConfig{})
+		first, _ := client.Get(server.URL+"/max-age", nil)
+		second, _ := client.Get(server.URL+"/max-age", nil)
+		assert.Equal(t, first, second)
+
+		third, _ := client.GetCtx(WithCacheBypass(ctx), server.URL+"/max-age", nil)
+		assert.NotEqual(t, first, third)
+		fourth, _ := client.Get(server.URL+"/max-age", map[string]string{"Cache-Control": "no-cache"})
+		assert.NotEqual(t, third, fourth)
+	})
+
+	t.Run("etag and last-modified revalidation", func(t *testing.T) {
+		client, _ := newCachedClient(CacheConfig{})
+		for _, path := range []string{"/etag", "/last-modified"} {
+			atomic.StoreInt32(&notModified, 0)
+			first, _ := client.Get(server.URL+path, nil)
+			response, err := client.NewRequest(http.MethodGet, server.URL+path).Send(ctx)
+			assert.NoError(t, err)
+			_ = response.Body.Close()
+			assert.Equal(t, "revalidated", response.Header.Get(CacheStatusHeader))
+			second, _ := client.Get(server.URL+path, nil)
+			assert.Equal(t, first, second)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&notModified))
+		}
+	})
+
+	t.Run("no-store and default ttl", func(t *testing.T) {
+		client, cache := newCachedClient(CacheConfig{})
+		first, _ := client.Get(server.URL+"/no-store", nil)
+		second, _ := client.Get(server.URL+"/no-store", nil)
+		assert.NotEqual(t, first, second)
+		first, _ = client.Get(server.URL+"/plain", nil)
+		second, _ = client.Get(server.URL+"/plain", nil)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, 0, cache.Len())
+
+		client, _ = newCachedClient(CacheConfig{DefaultTTL: time.Minute})
+		first, _ = client.Get(server.URL+"/plain", nil)
+		second, _ = client.Get(server.URL+"/plain", nil)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("etag without freshness", func(t *testing.T) {
+		// DefaultTTL 为 0 时不缓存, 不会每次都发送条件请求
+		atomic.StoreInt32(&notModified, 0)
+		client, cache := newCachedClient(CacheConfig{})
+		first, _ := client.Get(server.URL+"/etag-only", nil)
+		second, _ := client.Get(server.URL+"/etag-only", nil)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, 0, cache.Len())
+		assert.Equal(t, int32(0), atomic.LoadInt32(&notModified))
+
+		// 有 DefaultTTL 时在有效期内直接命中
+		client, _ = newCachedClient(CacheConfig{DefaultTTL: time.Minute})
+		_, _ = client.Get(server.URL+"/etag-only", nil)
+		response, err := client.NewRequest(http.MethodGet, server.URL+"/etag-only").Send(ctx)
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, "hit", response.Header.Get(CacheStatusHeader))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&notModified))
+	})
+
+	t.Run("bounded entries", func(t *testing.T) {
+		client, cache := newCachedClient(CacheConfig{MaxEntries: 2})
+		for i := 0; i < 3; i++ {
+			_, err := client.Get(server.URL+"/max-age?i="+strconv.Itoa(i), nil)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, cache.Len())
+		response, _ := client.NewRequest(http.MethodGet, server.URL+"/max-age?i=0").Send(ctx)
+		_ = response.Body.Close()
+		assert.Equal(t, "miss", response.Header.Get(CacheStatusHeader))
+		cache.Purge()
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		client, cache := newCachedClient(CacheConfig{})
+		en, _ := client.Get(server.URL+"/vary", map[string]string{"Accept-Language": "en"})
+		zh, _ := client.Get(server.URL+"/vary", map[string]string{"Accept-Language": "zh"})
+		assert.NotEqual(t, en, zh)
+		cachedEn, _ := client.Get(server.URL+"/vary", map[string]string{"Accept-Language": "en"})
+		cachedZh, _ := client.Get(server.URL+"/vary", map[string]string{"Accept-Language": "zh"})
+		assert.Equal(t, en, cachedEn)
+		assert.Equal(t, zh, cachedZh)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("authorization", func(t *testing.T) {
+		client, cache := newCachedClient(CacheConfig{})
+		alice, _ := client.Get(server.URL+"/max-age?auth", map[string]string{"Authorization": "Bearer alice"})
+		bob, _ := client.Get(server.URL+"/max-age?auth", map[string]string{"Authorization": "Bearer bob"})
+		assert.NotEqual(t, alice, bob)
+		assert.Equal(t, 0, cache.Len())
+
+		first, _ := client.Get(server.URL+"/public", map[string]string{"Authorization": "Bearer alice"})
+		second, _ := client.Get(server.URL+"/public", map[string]string{"Authorization": "Bearer bob"})
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("unknown length over max bytes", func(t *testing.T) {
+		client, cache := newCachedClient(CacheConfig{MaxBytes: 40})
+		// 超过 MaxBytes 后不再继续缓冲, 响应在服务端发送完之前返回
+		sent := make(chan *http.Response)
+		go func() {
+			response, err := client.NewRequest(http.MethodGet, server.URL+"/chunked?wait").Send(ctx)
+			assert.NoError(t, err)
+			sent <- response
+		}()
+		var response *http.Response
+		select {
+		case response = <-sent:
+		case <-time.After(time.Second):
+			close(release)
+			t.Fatal("response body is buffered beyond MaxBytes")
+		}
+		close(release)
+		body, err := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("x", 64), string(body))
+		assert.Equal(t, 0, cache.Len())
+
+		client, cache = newCachedClient(CacheConfig{MaxBytes: 64})
+		body, err = client.Get(server.URL+"/chunked", nil)
+		assert.NoError(t, err)
+		assert.Len(t, body, 64)
+		assert.Equal(t, 1, cache.Len())
+	})
+}
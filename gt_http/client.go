@@ -0,0 +1,221 @@
+package gt_http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClientConfig HTTP客户端配置
+type ClientConfig struct {
+	ConnectTimeout      time.Duration     `mapstructure:"connect_timeout"`         // 建立连接超时
+	ReadTimeout         time.Duration     `mapstructure:"read_timeout"`            // 等待响应头超时
+	Timeout             time.Duration     `mapstructure:"timeout"`                 // 一次调用的总超时, 含所有重试和读取响应体, 0 表示不限制; 仅 Request.Do 及基于它的 Get/Post/DoJSON 等生效, Client.Do、Request.Send 和流式读取由 ctx 控制
+	MaxIdleConns        int               `mapstructure:"max_idle_conns"`          // 连接池最大空闲连接数
+	MaxIdleConnsPerHost int               `mapstructure:"max_idle_conns_per_host"` // 每个host最大空闲连接数
+	IdleConnTimeout     time.Duration     `mapstructure:"idle_conn_timeout"`       // 空闲连接保持时间
+	Retry               *RetryConfig      `mapstructure:"retry"`                   // 重试策略, nil 表示不重试
+	DisableTraceHeaders bool              `mapstructure:"disable_trace_headers"`   // 不向下游传递 x-req-id / x-tra-id
+	AcceptedStatus      []int             `mapstructure:"accepted_status"`         // 视为成功的状态码, 为空时接受所有 2xx
+	MaxErrorBodySize    int               `mapstructure:"max_error_body_size"`     // StatusError 保留的响应体字节数, 默认 DefaultMaxErrorBodySize
+	Proxy               string            `mapstructure:"proxy"`                   // 代理地址 http:// https:// socks5://, 为空时使用环境变量 HTTP_PROXY 等
+	DNSServer           string            `mapstructure:"dns_server"`              // 自定义DNS服务器, 如 8.8.8.8:53
+	HostOverrides       map[string]string `mapstructure:"host_overrides"`          // 静态解析, host 或 host:port 映射到 ip 或 ip:port, 用于测试
+	TLS                 *TLSConfig        `mapstructure:"tls"`                     // TLS 配置
+
+	// Transport 替换底层的 *http.Transport, 如测试时使用 http_mock.MockTransport; 连接相关配置对其不生效
+	Transport http.RoundTripper `mapstructure:"-"`
+}
+
+// DefaultClientConfig 默认客户端配置
+var DefaultClientConfig = ClientConfig{
+	ConnectTimeout:      5 * time.Second,
+	ReadTimeout:         30 * time.Second,
+	Timeout:             60 * time.Second,
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// Client 可复用的HTTP客户端, 内部共享连接池, 并发安全
+type Client struct {
+	config      ClientConfig
+	transport   http.RoundTripper
+	httpClient  *http.Client
+	middlewares []Middleware
+}
+
+// DefaultClient HttpGet/HttpPost 使用的默认客户端
+var DefaultClient = NewClient(DefaultClientConfig)
+
+// NewClient 创建HTTP客户端, 代理地址、TLS证书文件或固定公钥配置错误时 panic
+// 仅用于配置确定有效的场景, 如 DefaultClientConfig; 配置来自配置文件等外部输入时使用 TryNewClient
+// @param config ClientConfig 客户端配置
+func NewClient(config ClientConfig) *Client {
+	client, err := TryNewClient(config)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// TryNewClient 创建HTTP客户端, 代理地址、TLS证书文件或固定公钥配置错误时返回错误
+// @param config ClientConfig 客户端配置
+// @return *Client 客户端
+// @return error 配置错误
+func TryNewClient(config ClientConfig) (*Client, error) {
+	transport := config.Transport
+	if transport == nil {
+		t, err := NewTransport(config)
+		if err != nil {
+			return nil, err
+		}
+		transport = t
+	}
+	return &Client{
+		config:     config,
+		transport:  transport,
+		httpClient: &http.Client{Transport: transport},
+	}, nil
+}
+
+// Config 获取客户端配置
+func (c *Client) Config() ClientConfig {
+	return c.config
+}
+
+// HttpClient 获取底层的 *http.Client
+func (c *Client) HttpClient() *http.Client {
+	return c.httpClient
+}
+
+// CloseIdleConnections 关闭连接池中的空闲连接
+func (c *Client) CloseIdleConnections() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// Do 发送请求, 按重试策略重试网络错误和指定状态码, 请求体不可重放 (无 GetBody) 时不重试
+// 请求的 context 中带有 log_context 的 reqId / traId 时, 自动写入 x-req-id / x-tra-id 请求头
+// 不应用 ClientConfig.Timeout, 超时由 request 的 context 控制; 调用方负责关闭返回的 response.Body
+// @param request *http.Request 请求
+func (c *Client) Do(request *http.Request) (*http.Response, error) {
+	if !c.config.DisableTraceHeaders {
+		SetTraceHeaders(request.Context(), request.Header)
+	}
+
+	retry := c.config.Retry
+	// 请求体不可重放时只发送一次, 返回原始响应而不是重试失败
+	if retry == nil || retry.MaxAttempts <= 1 || !retry.canRetry(request) || !canRewind(request) {
+		return c.httpClient.Do(request)
+	}
+
+	ctx := request.Context()
+	for attempt := 1; ; attempt++ {
+		req := request
+		if attempt > 1 {
+			var err error
+			if req, err = rewindRequest(request); err != nil {
+				return nil, err
+			}
+		}
+
+		response, err := c.httpClient.Do(req)
+		if attempt >= retry.MaxAttempts || !retry.shouldRetry(response, err) {
+			return response, err
+		}
+
+		wait := retry.backoff(attempt, response)
+		if response != nil {
+			// 读完响应体以便连接回到连接池
+			_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 4096))
+			_ = response.Body.Close()
+		}
+		if err = sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Get 发送GET请求
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func (c *Client) Get(url string, header map[string]string) ([]byte, error) {
+	return c.GetCtx(context.Background(), url, header)
+}
+
+// GetCtx 发送GET请求, 遵循 ctx 的取消和超时
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func (c *Client) GetCtx(ctx context.Context, url string, header map[string]string) ([]byte, error) {
+	return c.send(ctx, http.MethodGet, url, header, nil)
+}
+
+// Post 发送POST请求, 请求数据以JSON编码
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData any 请求数据
+func (c *Client) Post(url string, header map[string]string, reqData any) ([]byte, error) {
+	return c.PostCtx(context.Background(), url, header, reqData)
+}
+
+// PostCtx 发送POST请求, 请求数据以JSON编码, 遵循 ctx 的取消和超时
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData any 请求数据
+func (c *Client) PostCtx(ctx context.Context, url string, header map[string]string, reqData any) ([]byte, error) {
+	reqBody, err := json.Marshal(reqData)
+	if err != nil {
+		return nil, err
+	}
+	return c.send(ctx, http.MethodPost, url, header, reqBody)
+}
+
+// send 发送请求并读取全部响应体, 请求体以JSON编码
+func (c *Client) send(ctx context.Context, method string, url string, header map[string]string, reqBody []byte) ([]byte, error) {
+	request := c.NewRequest(method, url).SetHeaders(header)
+	if reqBody != nil {
+		request.SetRawBody(ContentTypeJSON, reqBody)
+	}
+	return request.Do(ctx)
+}
+
+// canRewind 判断请求体能否重放
+func canRewind(request *http.Request) bool {
+	return request.Body == nil || request.Body == http.NoBody || request.GetBody != nil
+}
+
+// rewindRequest 复制请求并重置请求体, 用于重试
+func rewindRequest(request *http.Request) (*http.Request, error) {
+	req := request.Clone(request.Context())
+	if request.Body == nil || request.Body == http.NoBody {
+		return req, nil
+	}
+	if request.GetBody == nil {
+		return nil, errors.New("请求体不可重放, 无法重试")
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+	return req, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
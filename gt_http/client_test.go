@@ -0,0 +1,169 @@
+package gt_http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryClient(maxAttempts int) *Client {
+	config := DefaultClientConfig
+	config.Retry = &RetryConfig{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+	return NewClient(config)
+}
+
+func TestHttpGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "v", r.Header.Get("X-Test"))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	body, err := HttpGet(server.URL, map[string]string{"X-Test": "v"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestHttpPost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	body, err := HttpPost(server.URL, nil, map[string]int{"a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+}
+
+func TestClient_Retry(t *testing.T) {
+	t.Run("retry on status", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		body, err := newRetryClient(3).Get(server.URL, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", string(body))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("give up after max attempts", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		_, err := newRetryClient(2).Get(server.URL, nil)
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("post is not retried", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := newRetryClient(3).Post(server.URL, nil, map[string]int{"a": 1})
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("post with idempotency key is retried with body", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			assert.Equal(t, `{"a":1}`, string(data))
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write(data)
+		}))
+		defer server.Close()
+
+		header := map[string]string{IdempotencyKeyHeader: "k1"}
+		body, err := newRetryClient(3).Post(server.URL, header, map[string]int{"a": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("body without GetBody is sent once", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("maintenance"))
+		}))
+		defer server.Close()
+
+		// http.NewRequest only sets GetBody for bytes, strings and bytes.Reader bodies
+		request, err := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("data")))
+		assert.NoError(t, err)
+		response, err := newRetryClient(3).Do(request)
+		if assert.NoError(t, err) {
+			defer response.Body.Close()
+			body, _ := io.ReadAll(response.Body)
+			assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+			assert.Equal(t, "maintenance", string(body))
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("timeout covers all attempts", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(30 * time.Millisecond)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := newRetryClient(10)
+		client.config.Timeout = 80 * time.Millisecond
+		_, err := client.Get(server.URL, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, atomic.LoadInt32(&calls), int32(10))
+	})
+}
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	retry := &RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, retry.backoff(1, nil))
+	assert.Equal(t, 200*time.Millisecond, retry.backoff(2, nil))
+	assert.Equal(t, 300*time.Millisecond, retry.backoff(3, nil))
+
+	retry.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		wait := retry.backoff(1, nil)
+		assert.True(t, wait >= 50*time.Millisecond && wait <= 100*time.Millisecond)
+	}
+
+	response := &http.Response{Header: http.Header{"Retry-After": []string{"1"}}}
+	assert.Equal(t, 300*time.Millisecond, retry.backoff(1, response))
+}
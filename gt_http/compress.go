@@ -0,0 +1,157 @@
+package gt_http
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// 压缩编码
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+)
+
+// CompressionConfig 压缩中间件配置
+type CompressionConfig struct {
+	RequestEncoding     string `mapstructure:"request_encoding"`      // 请求体压缩编码 gzip / deflate / br, 为空时不压缩请求体
+	MinSize             int    `mapstructure:"min_size"`              // 请求体达到该字节数才压缩, 默认 1024
+	DisableDecompress   bool   `mapstructure:"disable_decompress"`    // 不自动解压响应体
+	DisableAcceptHeader bool   `mapstructure:"disable_accept_header"` // 不自动添加 Accept-Encoding 请求头
+}
+
+// NewCompressionMiddleware 创建压缩中间件
+// 按配置压缩请求体, 并透明解压 gzip / deflate / br 编码的响应体
+// @param config CompressionConfig 压缩配置
+func NewCompressionMiddleware(config CompressionConfig) Middleware {
+	if config.MinSize <= 0 {
+		config.MinSize = 1024
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			if config.RequestEncoding != "" || (!config.DisableDecompress && !config.DisableAcceptHeader) {
+				request = request.Clone(request.Context())
+			}
+			if config.RequestEncoding != "" && request.Header.Get("Content-Encoding") == "" {
+				if err := compressRequestBody(request, config.RequestEncoding, config.MinSize); err != nil {
+					return nil, err
+				}
+			}
+			if !config.DisableDecompress && !config.DisableAcceptHeader && request.Header.Get("Accept-Encoding") == "" {
+				request.Header.Set("Accept-Encoding", "gzip, deflate, br")
+			}
+
+			response, err := next.RoundTrip(request)
+			if err != nil || config.DisableDecompress {
+				return response, err
+			}
+			if err = decompressResponseBody(response); err != nil {
+				_ = response.Body.Close()
+				return nil, err
+			}
+			return response, nil
+		})
+	}
+}
+
+func compressRequestBody(request *http.Request, encoding string, minSize int) error {
+	body, err := readFullRequestBody(request)
+	if err != nil || len(body) < minSize {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	var writer io.WriteCloser
+	switch encoding {
+	case EncodingGzip:
+		writer = gzip.NewWriter(buf)
+	case EncodingDeflate:
+		writer = zlib.NewWriter(buf)
+	case EncodingBrotli:
+		writer = brotli.NewWriter(buf)
+	default:
+		return fmt.Errorf("不支持的压缩编码: %s", encoding)
+	}
+	if _, err = writer.Write(body); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	setRequestBody(request, buf.Bytes())
+	request.Header.Set("Content-Encoding", encoding)
+	return nil
+}
+
+func decompressResponseBody(response *http.Response) error {
+	encoding := strings.ToLower(strings.TrimSpace(response.Header.Get("Content-Encoding")))
+	if response.Body == nil || response.Body == http.NoBody {
+		return nil
+	}
+
+	var reader io.Reader
+	var closer func() error
+	switch encoding {
+	case EncodingGzip, "x-gzip":
+		gzipReader, err := gzip.NewReader(response.Body)
+		if err != nil {
+			// 空响应体
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		reader, closer = gzipReader, gzipReader.Close
+	case EncodingDeflate:
+		reader, closer = newDeflateReader(response.Body)
+	case EncodingBrotli:
+		reader = brotli.NewReader(response.Body)
+	default:
+		return nil
+	}
+
+	if reader != nil {
+		response.Body = &decompressBody{Reader: reader, closeReader: closer, body: response.Body}
+	}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return nil
+}
+
+// newDeflateReader HTTP 的 deflate 编码应为 zlib 格式, 但部分服务端返回原始 deflate 数据, 两者都支持
+func newDeflateReader(body io.Reader) (io.Reader, func() error) {
+	buffered := bufio.NewReader(body)
+	header, _ := buffered.Peek(2)
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		if zlibReader, err := zlib.NewReader(buffered); err == nil {
+			return zlibReader, zlibReader.Close
+		}
+	}
+	flateReader := flate.NewReader(buffered)
+	return flateReader, flateReader.Close
+}
+
+type decompressBody struct {
+	io.Reader
+	closeReader func() error
+	body        io.ReadCloser
+}
+
+func (b *decompressBody) Close() error {
+	if b.closeReader != nil {
+		_ = b.closeReader()
+	}
+	return b.body.Close()
+}
@@ -0,0 +1,100 @@
+package gt_http
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressBytes(t *testing.T, encoding string, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	var writer io.WriteCloser
+	switch encoding {
+	case EncodingGzip:
+		writer = gzip.NewWriter(buf)
+	case EncodingDeflate:
+		writer = zlib.NewWriter(buf)
+	case EncodingBrotli:
+		writer = brotli.NewWriter(buf)
+	case "raw-deflate":
+		var err error
+		writer, err = flate.NewWriter(buf, flate.DefaultCompression)
+		assert.NoError(t, err)
+	}
+	_, err := writer.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestCompressionMiddleware_Response(t *testing.T) {
+	content := []byte(strings.Repeat("hello compression ", 100))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip, deflate, br", r.Header.Get("Accept-Encoding"))
+		encoding := r.URL.Query().Get("encoding")
+		header := encoding
+		if encoding == "raw-deflate" {
+			header = EncodingDeflate
+		}
+		w.Header().Set("Content-Encoding", header)
+		_, _ = w.Write(compressBytes(t, encoding, content))
+	}))
+	defer server.Close()
+
+	client := NewClient(DefaultClientConfig).Use(NewCompressionMiddleware(CompressionConfig{}))
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli, "raw-deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			body, err := client.Get(server.URL+"?encoding="+encoding, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, content, body)
+		})
+	}
+}
+
+func TestCompressionMiddleware_Request(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Encoding", r.Header.Get("Content-Encoding"))
+		var reader io.Reader = r.Body
+		switch r.Header.Get("Content-Encoding") {
+		case EncodingGzip:
+			reader, _ = gzip.NewReader(r.Body)
+		case EncodingDeflate:
+			reader, _ = zlib.NewReader(r.Body)
+		case EncodingBrotli:
+			reader = brotli.NewReader(r.Body)
+		}
+		_, _ = io.Copy(w, reader)
+	}))
+	defer server.Close()
+
+	large := map[string]string{"data": strings.Repeat("x", 2000)}
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli} {
+		t.Run(encoding, func(t *testing.T) {
+			client := NewClient(DefaultClientConfig).Use(NewCompressionMiddleware(CompressionConfig{RequestEncoding: encoding}))
+			response, err := client.NewRequest(http.MethodPost, server.URL).SetJSONBody(large).Send(context.Background())
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(response.Body)
+			_ = response.Body.Close()
+			assert.Equal(t, encoding, response.Header.Get("X-Content-Encoding"))
+			assert.Contains(t, string(body), strings.Repeat("x", 2000))
+		})
+	}
+
+	t.Run("small body not compressed", func(t *testing.T) {
+		client := NewClient(DefaultClientConfig).Use(NewCompressionMiddleware(CompressionConfig{RequestEncoding: EncodingGzip}))
+		response, err := client.NewRequest(http.MethodPost, server.URL).SetJSONBody(map[string]int{"a": 1}).Send(context.Background())
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, "", response.Header.Get("X-Content-Encoding"))
+	})
+}
@@ -0,0 +1,82 @@
+package gt_http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxErrorBodySize StatusError 默认保留的响应体字节数
+const DefaultMaxErrorBodySize = 4096
+
+// StatusError 响应状态码不在接受范围内时返回的错误, 可使用 errors.As 获取
+type StatusError struct {
+	StatusCode int         // 状态码
+	Header     http.Header // 响应头
+	Body       []byte      // 响应体, 超过 MaxErrorBodySize 的部分被截断
+	Truncated  bool        // 响应体是否被截断
+	Method     string      // 请求方法
+	URL        string      // 请求地址, 已隐藏密码
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("请求错误: %s %s 状态码 %d", e.Method, e.URL, e.StatusCode)
+	if len(e.Body) > 0 {
+		msg += ": " + string(e.Body)
+		if e.Truncated {
+			msg += "..."
+		}
+	}
+	return msg
+}
+
+// GetStatusCode 获取错误中的响应状态码
+// @param err error 错误
+// @return statusCode int 状态码, 非 StatusError 时返回 0
+func GetStatusCode(err error) (statusCode int) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// isAcceptedStatus 判断状态码是否被接受, 未配置时接受所有 2xx
+func (c *Client) isAcceptedStatus(statusCode int) bool {
+	if len(c.config.AcceptedStatus) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, status := range c.config.AcceptedStatus {
+		if status == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStatus 状态码不被接受时读取部分响应体并返回 *StatusError
+func (c *Client) checkStatus(response *http.Response) error {
+	if c.isAcceptedStatus(response.StatusCode) {
+		return nil
+	}
+	maxBodySize := c.config.MaxErrorBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxErrorBodySize
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, int64(maxBodySize)+1))
+	statusErr := &StatusError{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       body,
+	}
+	if len(body) > maxBodySize {
+		statusErr.Body = body[:maxBodySize]
+		statusErr.Truncated = true
+	}
+	if response.Request != nil {
+		statusErr.Method = response.Request.Method
+		statusErr.URL = response.Request.URL.Redacted()
+	}
+	return statusErr
+}
@@ -0,0 +1,66 @@
+package gt_http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		case "/large":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+		default:
+			w.Header().Set("X-Reason", "missing")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":404}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("status error carries response", func(t *testing.T) {
+		_, err := HttpGet(server.URL+"/missing?a=1", nil)
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		assert.Equal(t, "missing", statusErr.Header.Get("X-Reason"))
+		assert.Equal(t, `{"code":404}`, string(statusErr.Body))
+		assert.Equal(t, http.MethodGet, statusErr.Method)
+		assert.Equal(t, server.URL+"/missing?a=1", statusErr.URL)
+		assert.Equal(t, http.StatusNotFound, GetStatusCode(err))
+	})
+
+	t.Run("2xx accepted by default", func(t *testing.T) {
+		body, err := HttpGet(server.URL+"/created", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "created", string(body))
+	})
+
+	t.Run("accepted status set", func(t *testing.T) {
+		config := DefaultClientConfig
+		config.AcceptedStatus = []int{http.StatusOK}
+		_, err := NewClient(config).Get(server.URL+"/created", nil)
+		assert.Equal(t, http.StatusCreated, GetStatusCode(err))
+	})
+
+	t.Run("body truncated", func(t *testing.T) {
+		config := DefaultClientConfig
+		config.MaxErrorBodySize = 10
+		_, err := NewClient(config).Get(server.URL+"/large", nil)
+		var statusErr *StatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Len(t, statusErr.Body, 10)
+		assert.True(t, statusErr.Truncated)
+	})
+
+	assert.Equal(t, 0, GetStatusCode(errors.New("other")))
+}
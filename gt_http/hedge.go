@@ -0,0 +1,187 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoEndpoint 没有可用的节点
+var ErrNoEndpoint = errors.New("没有可用的节点")
+
+// Endpoint 镜像节点
+type Endpoint struct {
+	URL    string `mapstructure:"url"`    // 节点基础地址, 如 https://a.example.com
+	Weight int    `mapstructure:"weight"` // 权重, 最大的为主节点(相同时取靠前的), 其余节点权重越大越优先; 都为 0 时按配置顺序
+}
+
+// EndpointGroupConfig 多节点请求配置
+type EndpointGroupConfig struct {
+	Endpoints   []Endpoint    `mapstructure:"endpoints"`    // 节点列表
+	HedgeDelay  time.Duration `mapstructure:"hedge_delay"`  // 对冲延迟, 超过该时间未返回时并发请求下一个节点; 0 表示只在失败时切换
+	MaxAttempts int           `mapstructure:"max_attempts"` // 最多请求的节点数, 默认全部节点
+}
+
+// EndpointGroup 支持对冲请求和故障切换的多节点组
+type EndpointGroup struct {
+	config EndpointGroupConfig
+}
+
+// EndpointFunc 向指定节点发送请求, 须遵循 ctx 的取消
+type EndpointFunc func(ctx context.Context, endpoint Endpoint) ([]byte, error)
+
+// NewEndpointGroup 创建多节点组
+// @param config EndpointGroupConfig 多节点配置
+func NewEndpointGroup(config EndpointGroupConfig) *EndpointGroup {
+	if config.MaxAttempts <= 0 || config.MaxAttempts > len(config.Endpoints) {
+		config.MaxAttempts = len(config.Endpoints)
+	}
+	return &EndpointGroup{config: config}
+}
+
+// Do 先请求主节点, 再按权重顺序请求其余节点: 失败时立即切换到下一个节点, 开启对冲时超过 HedgeDelay 未返回也会并发请求下一个节点
+// 第一个成功的结果胜出, 其余请求通过 ctx 取消; 全部失败时返回所有节点的错误
+// @param ctx context.Context 上下文
+// @param fn EndpointFunc 请求函数
+// @return body []byte 胜出节点的结果
+// @return winner Endpoint 胜出的节点
+func (g *EndpointGroup) Do(ctx context.Context, fn EndpointFunc) (body []byte, winner Endpoint, err error) {
+	endpoints := g.order()
+	if len(endpoints) == 0 {
+		return nil, winner, ErrNoEndpoint
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		body     []byte
+		err      error
+		endpoint Endpoint
+	}
+	results := make(chan result, len(endpoints))
+	launched, pending := 0, 0
+	launch := func() {
+		endpoint := endpoints[launched]
+		launched++
+		pending++
+		go func() {
+			data, fnErr := fn(ctx, endpoint)
+			results <- result{data, fnErr, endpoint}
+		}()
+	}
+
+	var errs []error
+	launch()
+	for {
+		var hedge <-chan time.Time
+		var timer *time.Timer
+		if g.config.HedgeDelay > 0 && launched < len(endpoints) {
+			timer = time.NewTimer(g.config.HedgeDelay)
+			hedge = timer.C
+		}
+
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				stopTimer(timer)
+				if launched > 1 {
+					httpLogger.CInfow(ctx, "多节点请求完成", "winner", r.endpoint.URL, "launched", launched)
+				}
+				return r.body, r.endpoint, nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", r.endpoint.URL, r.err))
+			if launched < len(endpoints) {
+				launch()
+			} else if pending == 0 {
+				stopTimer(timer)
+				return nil, winner, errors.Join(errs...)
+			}
+		case <-hedge:
+			launch()
+		case <-ctx.Done():
+			stopTimer(timer)
+			return nil, winner, ctx.Err()
+		}
+		stopTimer(timer)
+	}
+}
+
+// Get 向节点组发送GET请求, 请求地址为节点地址拼接 path
+// @param ctx context.Context 上下文
+// @param c *Client 客户端
+// @param path string 请求路径
+// @param header map[string]string 请求头
+func (g *EndpointGroup) Get(ctx context.Context, c *Client, path string, header map[string]string) ([]byte, Endpoint, error) {
+	return g.Do(ctx, func(ctx context.Context, endpoint Endpoint) ([]byte, error) {
+		return c.NewRequest(http.MethodGet, joinURL(endpoint.URL, path)).SetHeaders(header).Do(ctx)
+	})
+}
+
+// Post 向节点组发送POST请求, 请求数据以JSON编码, 请求地址为节点地址拼接 path
+// 对冲或切换时同一请求可能被多个节点处理, 仅用于幂等接口
+// @param ctx context.Context 上下文
+// @param c *Client 客户端
+// @param path string 请求路径
+// @param header map[string]string 请求头
+// @param reqData any 请求数据
+func (g *EndpointGroup) Post(ctx context.Context, c *Client, path string, header map[string]string, reqData any) ([]byte, Endpoint, error) {
+	return g.Do(ctx, func(ctx context.Context, endpoint Endpoint) ([]byte, error) {
+		return c.PostCtx(ctx, joinURL(endpoint.URL, path), header, reqData)
+	})
+}
+
+// order 主节点固定为权重最大的节点, 相同时取配置中靠前的; 其余节点按权重随机排序, 权重越大越可能排在前面;
+// 权重为 0 的节点按配置顺序排在最后
+func (g *EndpointGroup) order() []Endpoint {
+	type weighted struct {
+		endpoint Endpoint
+		key      float64
+	}
+	items := make([]weighted, len(g.config.Endpoints))
+	for i, endpoint := range g.config.Endpoints {
+		key := math.Inf(-1)
+		if endpoint.Weight > 0 {
+			key = math.Log(rand.Float64()) / float64(endpoint.Weight)
+		}
+		items[i] = weighted{endpoint, key}
+	}
+	primary := 0
+	for i, endpoint := range g.config.Endpoints {
+		if endpoint.Weight > g.config.Endpoints[primary].Weight {
+			primary = i
+		}
+	}
+	if len(items) > 0 {
+		items[primary].key = math.Inf(1)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+
+	endpoints := make([]Endpoint, g.config.MaxAttempts)
+	for i := range endpoints {
+		endpoints[i] = items[i].endpoint
+	}
+	return endpoints
+}
+
+func joinURL(base string, path string) string {
+	if path == "" {
+		return base
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
@@ -0,0 +1,133 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointGroup_Failover(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+	good := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer good.Close()
+
+	group := NewEndpointGroup(EndpointGroupConfig{
+		Endpoints: []Endpoint{{URL: bad.URL}, {URL: good.URL + "/"}},
+	})
+	body, winner, err := group.Get(context.Background(), NewClient(DefaultClientConfig), "/lookup", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/lookup", string(body))
+	assert.Equal(t, good.URL+"/", winner.URL)
+
+	t.Run("all failed", func(t *testing.T) {
+		group := NewEndpointGroup(EndpointGroupConfig{
+			Endpoints: []Endpoint{{URL: bad.URL}, {URL: bad.URL}},
+		})
+		_, _, err := group.Get(context.Background(), NewClient(DefaultClientConfig), "/lookup", nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, GetStatusCode(err))
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		group := NewEndpointGroup(EndpointGroupConfig{
+			Endpoints:   []Endpoint{{URL: bad.URL}, {URL: good.URL}},
+			MaxAttempts: 1,
+		})
+		_, _, err := group.Get(context.Background(), NewClient(DefaultClientConfig), "/lookup", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("no endpoint", func(t *testing.T) {
+		_, _, err := NewEndpointGroup(EndpointGroupConfig{}).Do(context.Background(), nil)
+		assert.ErrorIs(t, err, ErrNoEndpoint)
+	})
+}
+
+func TestEndpointGroup_Hedge(t *testing.T) {
+	var cancelled atomic.Bool
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled.Store(true)
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	group := NewEndpointGroup(EndpointGroupConfig{
+		Endpoints:  []Endpoint{{URL: slow.URL}, {URL: fast.URL}},
+		HedgeDelay: 50 * time.Millisecond,
+	})
+	start := time.Now()
+	body, winner, err := group.Get(context.Background(), NewClient(DefaultClientConfig), "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", string(body))
+	assert.Equal(t, fast.URL, winner.URL)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	// 落败的请求被取消
+	assert.Eventually(t, cancelled.Load, time.Second, 10*time.Millisecond)
+
+	t.Run("primary wins before delay", func(t *testing.T) {
+		var calls atomic.Int32
+		group := NewEndpointGroup(EndpointGroupConfig{
+			Endpoints:  []Endpoint{{URL: "a"}, {URL: "b"}},
+			HedgeDelay: 100 * time.Millisecond,
+		})
+		_, winner, err := group.Do(context.Background(), func(ctx context.Context, endpoint Endpoint) ([]byte, error) {
+			calls.Add(1)
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "a", winner.URL)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("context cancel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		_, _, err := group.Do(ctx, func(ctx context.Context, endpoint Endpoint) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
+
+func TestEndpointGroup_Weight(t *testing.T) {
+	group := NewEndpointGroup(EndpointGroupConfig{
+		Endpoints: []Endpoint{{URL: "zero"}, {URL: "light", Weight: 1}, {URL: "heavy", Weight: 9}, {URL: "mid", Weight: 3}},
+	})
+	second := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		endpoints := group.order()
+		// 主节点固定为权重最大的节点, 对冲和切换才会用到其余节点
+		assert.Equal(t, "heavy", endpoints[0].URL)
+		second[endpoints[1].URL]++
+		// 权重为 0 的节点排在最后
+		assert.Equal(t, "zero", endpoints[3].URL)
+	}
+	assert.Greater(t, second["mid"], 600)
+	assert.Greater(t, second["light"], 0)
+
+	// 权重相同时取配置中靠前的节点
+	group = NewEndpointGroup(EndpointGroupConfig{Endpoints: []Endpoint{{URL: "a", Weight: 1}, {URL: "b", Weight: 5}, {URL: "c", Weight: 5}}})
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "b", group.order()[0].URL)
+	}
+}
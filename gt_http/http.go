@@ -1,57 +1,12 @@
 package gt_http
 
-import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"io"
-	"net/http"
-)
+import "context"
 
 // HttpGet 发送GET请求
 // @param url string 请求地址
 // @param header map[string]string 请求头
 func HttpGet(url string, header map[string]string) ([]byte, error) {
-	var err error
-	var respBody []byte
-
-	httpClient := new(http.Client)
-
-	request, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return respBody, err
-	}
-
-	request.Header.Set("Content-Type", "application/json")
-
-	for k, v := range header {
-		request.Header.Set(k, v)
-	}
-
-	response, err := httpClient.Do(request)
-	if err != nil {
-		return respBody, err
-	}
-
-	defer func() {
-		if response != nil && response.Body != nil {
-			err = response.Body.Close()
-			if err != nil {
-				return
-			}
-		}
-	}()
-
-	if response.StatusCode != http.StatusOK {
-		return respBody, errors.New("请求错误")
-	}
-
-	respBody, err = io.ReadAll(response.Body)
-	if err != nil {
-		return respBody, err
-	}
-
-	return respBody, nil
+	return DefaultClient.Get(url, header)
 }
 
 // HttpPost 发送POST请求
@@ -59,49 +14,22 @@ func HttpGet(url string, header map[string]string) ([]byte, error) {
 // @param header map[string]string 请求头
 // @param reqData any 请求数据
 func HttpPost(url string, header map[string]string, reqData any) ([]byte, error) {
-	var err error
-	var respBody []byte
-
-	httpClient := new(http.Client)
-
-	reqBody, err := json.Marshal(reqData)
-	if err != nil {
-		return respBody, err
-	}
-
-	request, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return respBody, err
-	}
-
-	request.Header.Set("Content-Type", "application/json")
-
-	for k, v := range header {
-		request.Header.Set(k, v)
-	}
-
-	response, err := httpClient.Do(request)
-	if err != nil {
-		return respBody, err
-	}
-
-	defer func() {
-		if response != nil && response.Body != nil {
-			err = response.Body.Close()
-			if err != nil {
-				return
-			}
-		}
-	}()
-
-	if response.StatusCode != http.StatusOK {
-		return respBody, errors.New("请求错误")
-	}
+	return DefaultClient.Post(url, header, reqData)
+}
 
-	respBody, err = io.ReadAll(response.Body)
-	if err != nil {
-		return respBody, err
-	}
+// HttpGetCtx 发送GET请求, 遵循 ctx 的取消和超时, 并传递 ctx 中的 reqId / traId
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func HttpGetCtx(ctx context.Context, url string, header map[string]string) ([]byte, error) {
+	return DefaultClient.GetCtx(ctx, url, header)
+}
 
-	return respBody, nil
+// HttpPostCtx 发送POST请求, 遵循 ctx 的取消和超时, 并传递 ctx 中的 reqId / traId
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData any 请求数据
+func HttpPostCtx(ctx context.Context, url string, header map[string]string, reqData any) ([]byte, error) {
+	return DefaultClient.PostCtx(ctx, url, header, reqData)
 }
@@ -0,0 +1,56 @@
+package http_mock
+
+import (
+	"testing"
+)
+
+// AssertCalled 断言匹配的请求至少发送过一次, 返回最后一个匹配的请求
+func (m *MockTransport) AssertCalled(t testing.TB, method string, pattern string) *RecordedRequest {
+	t.Helper()
+	requests := m.RequestsFor(method, pattern)
+	if len(requests) == 0 {
+		t.Errorf("http_mock: 期望请求 %s %s, 实际未发送", method, pattern)
+		return nil
+	}
+	return requests[len(requests)-1]
+}
+
+// AssertNotCalled 断言匹配的请求未发送过
+func (m *MockTransport) AssertNotCalled(t testing.TB, method string, pattern string) {
+	t.Helper()
+	if n := len(m.RequestsFor(method, pattern)); n > 0 {
+		t.Errorf("http_mock: 期望不发送请求 %s %s, 实际发送 %d 次", method, pattern, n)
+	}
+}
+
+// AssertCallCount 断言匹配的请求发送次数
+func (m *MockTransport) AssertCallCount(t testing.TB, method string, pattern string, count int) {
+	t.Helper()
+	if n := len(m.RequestsFor(method, pattern)); n != count {
+		t.Errorf("http_mock: 期望请求 %s %s 发送 %d 次, 实际 %d 次", method, pattern, count, n)
+	}
+}
+
+// AssertHeader 断言请求头的值
+func (r *RecordedRequest) AssertHeader(t testing.TB, key string, value string) {
+	t.Helper()
+	if r == nil {
+		t.Errorf("http_mock: 请求为空, 无法检查请求头 %s", key)
+		return
+	}
+	if got := r.Header.Get(key); got != value {
+		t.Errorf("http_mock: 请求头 %s 期望 %q, 实际 %q", key, value, got)
+	}
+}
+
+// AssertBody 断言请求体
+func (r *RecordedRequest) AssertBody(t testing.TB, body string) {
+	t.Helper()
+	if r == nil {
+		t.Errorf("http_mock: 请求为空, 无法检查请求体")
+		return
+	}
+	if got := string(r.Body); got != body {
+		t.Errorf("http_mock: 请求体期望 %q, 实际 %q", body, got)
+	}
+}
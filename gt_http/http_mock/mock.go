@@ -0,0 +1,289 @@
+package http_mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockTransport 可替换 gt_http.ClientConfig.Transport 的模拟传输层
+// 按添加顺序匹配路由返回预设响应, 并记录所有发出的请求
+type MockTransport struct {
+	mu       sync.Mutex
+	routes   []*Route
+	requests []*RecordedRequest
+}
+
+// NewMockTransport 创建模拟传输层
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// On 添加路由
+// @param method string 请求方法, 为空时匹配任意方法
+// @param pattern string 匹配规则: 以 / 开头时匹配路径, 否则匹配完整地址(不含查询参数); 以 * 结尾时按前缀匹配
+func (m *MockTransport) On(method string, pattern string) *Route {
+	return m.OnMatch(func(request *http.Request) bool {
+		return matchRequest(request, method, pattern)
+	})
+}
+
+// OnMatch 添加自定义匹配函数的路由
+// @param match func(request *http.Request) bool 匹配函数
+func (m *MockTransport) OnMatch(match func(request *http.Request) bool) *Route {
+	route := &Route{match: match, status: http.StatusOK, header: http.Header{}}
+	m.mu.Lock()
+	m.routes = append(m.routes, route)
+	m.mu.Unlock()
+	return route
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (m *MockTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	recorded, clone, err := recordRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, recorded)
+	var route *Route
+	for _, r := range m.routes {
+		if (r.times <= 0 || r.calls < r.times) && r.match(clone) {
+			route = r
+			route.calls++
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	if route == nil {
+		return nil, fmt.Errorf("http_mock: 没有匹配的路由 %s %s", request.Method, request.URL)
+	}
+	response, err := route.respond(clone)
+	if response != nil && response.Request == clone {
+		response.Request = request
+	}
+	return response, err
+}
+
+// Requests 获取所有已记录的请求
+func (m *MockTransport) Requests() []*RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	requests := make([]*RecordedRequest, len(m.requests))
+	copy(requests, m.requests)
+	return requests
+}
+
+// RequestsFor 获取匹配的已记录请求, 匹配规则同 On
+func (m *MockTransport) RequestsFor(method string, pattern string) []*RecordedRequest {
+	var requests []*RecordedRequest
+	for _, request := range m.Requests() {
+		if request.Match(method, pattern) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// Reset 清空路由和已记录的请求
+func (m *MockTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes = nil
+	m.requests = nil
+}
+
+// Route 模拟路由及其响应
+type Route struct {
+	match    func(request *http.Request) bool
+	status   int
+	header   http.Header
+	body     []byte
+	handler  func(request *http.Request) (*http.Response, error)
+	delay    time.Duration
+	err      error
+	failRate float64
+	times    int
+	calls    int
+}
+
+// Reply 设置响应状态码和响应体
+func (r *Route) Reply(status int, body string) *Route {
+	r.status = status
+	r.body = []byte(body)
+	return r
+}
+
+// ReplyJSON 设置响应状态码和JSON响应体
+func (r *Route) ReplyJSON(status int, data any) *Route {
+	body, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+	r.status = status
+	r.body = body
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Header 设置响应头
+func (r *Route) Header(key string, value string) *Route {
+	r.header.Set(key, value)
+	return r
+}
+
+// Handler 使用函数生成响应, 设置后 Reply 无效
+func (r *Route) Handler(handler func(request *http.Request) (*http.Response, error)) *Route {
+	r.handler = handler
+	return r
+}
+
+// Delay 模拟响应延迟, 期间请求 ctx 结束时返回其错误
+func (r *Route) Delay(delay time.Duration) *Route {
+	r.delay = delay
+	return r
+}
+
+// Fail 模拟网络错误
+func (r *Route) Fail(err error) *Route {
+	r.err = err
+	r.failRate = 1
+	return r
+}
+
+// FailRate 按概率模拟网络错误
+// @param rate float64 失败概率 0~1
+// @param err error 返回的错误
+func (r *Route) FailRate(rate float64, err error) *Route {
+	r.err = err
+	r.failRate = rate
+	return r
+}
+
+// Times 限制路由可匹配的次数, 用完后继续匹配后面的路由; 0 表示不限制
+func (r *Route) Times(times int) *Route {
+	r.times = times
+	return r
+}
+
+func (r *Route) respond(request *http.Request) (*http.Response, error) {
+	if r.delay > 0 {
+		if err := sleep(request.Context(), r.delay); err != nil {
+			return nil, err
+		}
+	}
+	if r.err != nil && rand.Float64() < r.failRate {
+		return nil, r.err
+	}
+	if r.handler != nil {
+		return r.handler(request)
+	}
+	return NewResponse(request, r.status, r.header.Clone(), r.body), nil
+}
+
+// NewResponse 创建响应
+// @param request *http.Request 请求
+// @param status int 状态码
+// @param header http.Header 响应头, 可为 nil
+// @param body []byte 响应体
+func NewResponse(request *http.Request, status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}
+}
+
+// RecordedRequest 已记录的请求
+type RecordedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+// JSON 将请求体解码到 v
+func (r *RecordedRequest) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Form 解析 application/x-www-form-urlencoded 请求体
+func (r *RecordedRequest) Form() (url.Values, error) {
+	return url.ParseQuery(string(r.Body))
+}
+
+// Match 判断请求是否匹配, 匹配规则同 MockTransport.On
+func (r *RecordedRequest) Match(method string, pattern string) bool {
+	return matchRequest(&http.Request{Method: r.Method, URL: r.URL}, method, pattern)
+}
+
+// recordRequest 读取并关闭请求体, 返回记录和带请求体副本的克隆请求供路由匹配和响应使用, 不修改原请求
+func recordRequest(request *http.Request) (*RecordedRequest, *http.Request, error) {
+	recorded := &RecordedRequest{
+		Method: request.Method,
+		URL:    request.URL,
+		Header: request.Header.Clone(),
+	}
+	if request.Body == nil || request.Body == http.NoBody {
+		return recorded, request, nil
+	}
+	body, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	recorded.Body = body
+	clone := request.Clone(request.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return recorded, clone, nil
+}
+
+func matchRequest(request *http.Request, method string, pattern string) bool {
+	if method != "" && !strings.EqualFold(method, request.Method) {
+		return false
+	}
+	target := request.URL.Path
+	if !strings.HasPrefix(pattern, "/") {
+		u := *request.URL
+		u.RawQuery = ""
+		u.Fragment = ""
+		target = u.String()
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(target, prefix)
+	}
+	return target == pattern
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
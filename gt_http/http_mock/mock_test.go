@@ -0,0 +1,111 @@
+package http_mock
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/INT-Game/go-tools/gt_http"
+	"github.com/stretchr/testify/assert"
+)
+
+func newClient(transport *MockTransport) *gt_http.Client {
+	config := gt_http.DefaultClientConfig
+	config.Transport = transport
+	return gt_http.NewClient(config)
+}
+
+func TestMockTransport(t *testing.T) {
+	transport := NewMockTransport()
+	transport.On(http.MethodGet, "/users/*").ReplyJSON(http.StatusOK, map[string]string{"name": "u"})
+	transport.On(http.MethodPost, "https://api.example.com/orders").Reply(http.StatusServiceUnavailable, "busy").Times(1)
+	transport.On(http.MethodPost, "https://api.example.com/orders").Reply(http.StatusCreated, "created").Header("X-Id", "1")
+	client := newClient(transport)
+	ctx := context.Background()
+
+	user, err := gt_http.DoJSON[map[string]string](ctx, client, http.MethodGet, "https://api.example.com/users/1?a=1", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "u", user["name"])
+
+	_, err = client.PostCtx(ctx, "https://api.example.com/orders", map[string]string{"X-Token": "t"}, map[string]int{"n": 1})
+	assert.Equal(t, http.StatusServiceUnavailable, gt_http.GetStatusCode(err))
+
+	body, err := client.PostCtx(ctx, "https://api.example.com/orders", nil, map[string]int{"n": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "created", string(body))
+
+	_, err = client.GetCtx(ctx, "https://api.example.com/unknown", nil)
+	assert.Error(t, err)
+
+	transport.AssertCallCount(t, http.MethodPost, "https://api.example.com/orders", 2)
+	transport.AssertNotCalled(t, http.MethodDelete, "/users/*")
+	first := transport.RequestsFor(http.MethodPost, "/orders")[0]
+	first.AssertHeader(t, "X-Token", "t")
+	first.AssertBody(t, `{"n":1}`)
+	var data map[string]int
+	assert.NoError(t, transport.AssertCalled(t, "", "/orders").JSON(&data))
+	assert.Equal(t, 2, data["n"])
+	assert.Len(t, transport.Requests(), 4)
+
+	transport.Reset()
+	assert.Empty(t, transport.Requests())
+}
+
+func TestMockTransport_Injection(t *testing.T) {
+	transport := NewMockTransport()
+	errDown := errors.New("connection refused")
+	transport.On("", "/down").Fail(errDown)
+	transport.On("", "/slow").Delay(200*time.Millisecond).Reply(http.StatusOK, "slow")
+	transport.On("", "/custom").Handler(func(request *http.Request) (*http.Response, error) {
+		return NewResponse(request, http.StatusAccepted, nil, []byte(request.URL.Query().Get("q"))), nil
+	})
+	client := newClient(transport)
+
+	_, err := client.Get("http://svc/down", nil)
+	assert.ErrorIs(t, err, errDown)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetCtx(ctx, "http://svc/slow", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	body, err := client.Get("http://svc/custom?q=hello", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMockTransport_RequestUnchanged(t *testing.T) {
+	transport := NewMockTransport()
+	transport.On(http.MethodPost, "/echo").Handler(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		return NewResponse(request, http.StatusOK, nil, body), err
+	})
+
+	body := &closeRecorder{Reader: strings.NewReader("data")}
+	request, err := http.NewRequest(http.MethodPost, "https://api.example.com/echo", body)
+	assert.NoError(t, err)
+	response, err := transport.RoundTrip(request)
+	assert.NoError(t, err)
+
+	// the body is consumed and closed as a RoundTripper must, but the request itself is not modified
+	assert.Same(t, body, request.Body)
+	assert.True(t, body.closed)
+	assert.Same(t, request, response.Request)
+	data, _ := io.ReadAll(response.Body)
+	assert.Equal(t, "data", string(data))
+	transport.AssertCalled(t, http.MethodPost, "/echo").AssertBody(t, "data")
+}
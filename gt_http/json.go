@@ -0,0 +1,138 @@
+package gt_http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// maxDecodeErrorBodySize DecodeError 保留的响应体字节数
+const maxDecodeErrorBodySize = 512
+
+// DecodeError 响应体JSON解码失败时返回的错误, 可使用 errors.As 获取
+type DecodeError struct {
+	Err       error  // 解码错误
+	Body      []byte // 响应体片段
+	Truncated bool   // 响应体是否被截断
+	Method    string // 请求方法
+	URL       string // 请求地址, 已隐藏密码
+}
+
+func (e *DecodeError) Error() string {
+	body := string(e.Body)
+	if e.Truncated {
+		body += "..."
+	}
+	return fmt.Sprintf("响应解析错误: %s %s: %v, body: %s", e.Method, e.URL, e.Err, body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// redactURL 隐藏地址中的密码, 与 StatusError 一致; 无法解析时只保留 ? 之前的部分
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		rawURL, _, _ = strings.Cut(rawURL, "?")
+		return rawURL
+	}
+	return u.Redacted()
+}
+
+// isNil 判断 v 是否为 nil 或包含 nil 的指针、map、切片, 这些值会被编码为 null
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	switch value := reflect.ValueOf(v); value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
+
+// DoJSON 使用指定客户端发送请求, 请求数据以JSON编码, 并将响应体解码为 Resp
+// 响应体为空时返回 Resp 的零值
+// @param ctx context.Context 上下文
+// @param c *Client 客户端
+// @param method string 请求方法
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData any 请求数据, nil 或 nil 指针/map/切片表示无请求体
+func DoJSON[Resp any](ctx context.Context, c *Client, method string, url string, header map[string]string, reqData any) (Resp, error) {
+	var resp Resp
+
+	var reqBody []byte
+	if !isNil(reqData) {
+		var err error
+		if reqBody, err = json.Marshal(reqData); err != nil {
+			return resp, err
+		}
+	}
+
+	respBody, err := c.send(ctx, method, url, header, reqBody)
+	if err != nil {
+		return resp, err
+	}
+	if len(respBody) == 0 {
+		return resp, nil
+	}
+
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		decodeErr := &DecodeError{Err: err, Body: respBody, Method: method, URL: redactURL(url)}
+		if len(respBody) > maxDecodeErrorBodySize {
+			decodeErr.Body = respBody[:maxDecodeErrorBodySize]
+			decodeErr.Truncated = true
+		}
+		return resp, decodeErr
+	}
+	return resp, nil
+}
+
+// GetJSON 发送GET请求, 并将响应体解码为 T
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func GetJSON[T any](ctx context.Context, url string, header map[string]string) (T, error) {
+	return DoJSON[T](ctx, DefaultClient, http.MethodGet, url, header, nil)
+}
+
+// PostJSON 发送POST请求, 请求数据以JSON编码, 并将响应体解码为 Resp
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData Req 请求数据
+func PostJSON[Req any, Resp any](ctx context.Context, url string, header map[string]string, reqData Req) (Resp, error) {
+	return DoJSON[Resp](ctx, DefaultClient, http.MethodPost, url, header, reqData)
+}
+
+// PutJSON 发送PUT请求, 请求数据以JSON编码, 并将响应体解码为 Resp
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData Req 请求数据
+func PutJSON[Req any, Resp any](ctx context.Context, url string, header map[string]string, reqData Req) (Resp, error) {
+	return DoJSON[Resp](ctx, DefaultClient, http.MethodPut, url, header, reqData)
+}
+
+// PatchJSON 发送PATCH请求, 请求数据以JSON编码, 并将响应体解码为 Resp
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param reqData Req 请求数据
+func PatchJSON[Req any, Resp any](ctx context.Context, url string, header map[string]string, reqData Req) (Resp, error) {
+	return DoJSON[Resp](ctx, DefaultClient, http.MethodPatch, url, header, reqData)
+}
+
+// DeleteJSON 发送DELETE请求, 并将响应体解码为 T
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func DeleteJSON[T any](ctx context.Context, url string, header map[string]string) (T, error) {
+	return DoJSON[T](ctx, DefaultClient, http.MethodDelete, url, header, nil)
+}
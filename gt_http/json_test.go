@@ -0,0 +1,86 @@
+package gt_http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONHelpers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			if r.Method == http.MethodDelete {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			var user testUser
+			if r.Method != http.MethodGet {
+				_ = json.NewDecoder(r.Body).Decode(&user)
+			} else {
+				user = testUser{Id: 1, Name: "get"}
+			}
+			user.Name += "-" + r.Method
+			_ = json.NewEncoder(w).Encode(user)
+		case "/echo":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.NewEncoder(w).Encode(map[string]string{"content_type": r.Header.Get("Content-Type"), "body": string(body)})
+		default:
+			_, _ = w.Write([]byte("<html>oops</html>"))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	user, err := GetJSON[testUser](ctx, server.URL+"/user", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testUser{Id: 1, Name: "get-GET"}, user)
+
+	user, err = PostJSON[testUser, testUser](ctx, server.URL+"/user", nil, testUser{Id: 2, Name: "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, testUser{Id: 2, Name: "a-POST"}, user)
+
+	user, err = PutJSON[testUser, testUser](ctx, server.URL+"/user", nil, testUser{Id: 3, Name: "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, testUser{Id: 3, Name: "b-PUT"}, user)
+
+	user, err = PatchJSON[testUser, testUser](ctx, server.URL+"/user", nil, testUser{Id: 4, Name: "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, testUser{Id: 4, Name: "c-PATCH"}, user)
+
+	user, err = DeleteJSON[testUser](ctx, server.URL+"/user", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testUser{}, user)
+
+	// typed nil request data sends no body instead of null
+	echo, err := PostJSON[*testUser, map[string]string](ctx, server.URL+"/echo", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"content_type": "", "body": ""}, echo)
+	echo, err = PostJSON[*testUser, map[string]string](ctx, server.URL+"/echo", nil, &testUser{Id: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, echo["content_type"])
+
+	_, err = GetJSON[testUser](ctx, server.URL+"/html", nil)
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.Equal(t, "<html>oops</html>", string(decodeErr.Body))
+	assert.Contains(t, err.Error(), "<html>oops</html>")
+
+	// credentials in the url are not leaked by the error
+	_, err = GetJSON[testUser](ctx, strings.Replace(server.URL, "http://", "http://user:secret@", 1)+"/html", nil)
+	assert.True(t, errors.As(err, &decodeErr))
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Contains(t, decodeErr.URL, "user:xxxxx@")
+}
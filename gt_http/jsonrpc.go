@@ -0,0 +1,409 @@
+package gt_http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONRPCVersion JSON-RPC 协议版本
+const JSONRPCVersion = "2.0"
+
+// JSON-RPC 预定义错误码
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+)
+
+// RPCError JSON-RPC 错误对象, 客户端可使用 errors.As 获取, 服务端方法返回该错误时原样响应
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	if len(e.Data) > 0 {
+		return fmt.Sprintf("JSON-RPC 错误: code=%d, message=%s, data=%s", e.Code, e.Message, e.Data)
+	}
+	return fmt.Sprintf("JSON-RPC 错误: code=%d, message=%s", e.Code, e.Message)
+}
+
+// NewRPCError 创建 JSON-RPC 错误
+// @param code int 错误码
+// @param message string 错误信息
+// @param data any 附加数据, nil 表示无
+func NewRPCError(code int, message string, data any) *RPCError {
+	rpcErr := &RPCError{Code: code, Message: message}
+	if data != nil {
+		rpcErr.Data, _ = json.Marshal(data)
+	}
+	return rpcErr
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// RPCClient JSON-RPC 2.0 客户端, 请求 id 自增分配
+type RPCClient struct {
+	client *Client
+	url    string
+	header map[string]string
+	nextID atomic.Uint64
+}
+
+// RPCCall 批量调用中的一次调用
+type RPCCall struct {
+	Method string // 方法名
+	Params any    // 参数, nil 表示无参数
+	Result any    // 结果解码目标指针, nil 表示忽略结果
+	Notify bool   // 是否为通知, 通知没有响应
+	Error  error  // 调用错误, 服务端返回错误时为 *RPCError
+}
+
+// NewRPCClient 创建 JSON-RPC 客户端
+// @param c *Client 客户端, nil 时使用 DefaultClient
+// @param url string 服务地址
+// @param header map[string]string 请求头
+func NewRPCClient(c *Client, url string, header map[string]string) *RPCClient {
+	if c == nil {
+		c = DefaultClient
+	}
+	return &RPCClient{client: c, url: url, header: header}
+}
+
+// Call 调用方法并将结果解码到 result
+// @param ctx context.Context 上下文
+// @param method string 方法名
+// @param params any 参数, nil 表示无参数
+// @param result any 结果解码目标指针, nil 表示忽略结果
+func (r *RPCClient) Call(ctx context.Context, method string, params any, result any) error {
+	call := &RPCCall{Method: method, Params: params, Result: result}
+	if err := r.Batch(ctx, call); err != nil {
+		return err
+	}
+	return call.Error
+}
+
+// Notify 发送通知, 不等待结果
+// @param ctx context.Context 上下文
+// @param method string 方法名
+// @param params any 参数, nil 表示无参数
+func (r *RPCClient) Notify(ctx context.Context, method string, params any) error {
+	return r.Batch(ctx, &RPCCall{Method: method, Params: params, Notify: true})
+}
+
+// Batch 批量调用, 只有一个调用时按单次调用发送
+// 请求失败时返回错误, 各调用的错误保存在 RPCCall.Error
+// @param ctx context.Context 上下文
+// @param calls ...*RPCCall 调用列表
+func (r *RPCClient) Batch(ctx context.Context, calls ...*RPCCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	requests := make([]rpcRequest, len(calls))
+	pending := map[string]*RPCCall{}
+	for i, call := range calls {
+		request := rpcRequest{JSONRPC: JSONRPCVersion, Method: call.Method}
+		if call.Params != nil {
+			params, err := json.Marshal(call.Params)
+			if err != nil {
+				return err
+			}
+			request.Params = params
+		}
+		if !call.Notify {
+			id := strconv.FormatUint(r.nextID.Add(1), 10)
+			request.ID = json.RawMessage(id)
+			pending[id] = call
+		}
+		requests[i] = request
+	}
+
+	var reqData any = requests
+	if len(requests) == 1 {
+		reqData = requests[0]
+	}
+	reqBody, err := json.Marshal(reqData)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	respBody, err := r.client.NewRequest(http.MethodPost, r.url).
+		SetHeaders(r.header).
+		SetRawBody(ContentTypeJSON, reqBody).
+		Do(ctx)
+	if err != nil {
+		rpcLogger.CWarnw(ctx, "JSON-RPC 请求失败", "url", r.url, "calls", len(calls), "error", err)
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	responses, err := r.decodeResponses(respBody)
+	if err != nil {
+		return err
+	}
+	for _, response := range responses {
+		call, ok := pending[string(bytes.TrimSpace(response.ID))]
+		if !ok {
+			// 服务端无法识别请求时返回 id 为 null 的错误
+			if response.Error != nil && len(responses) == 1 {
+				for _, c := range pending {
+					c.Error = response.Error
+				}
+				pending = nil
+			}
+			continue
+		}
+		delete(pending, string(bytes.TrimSpace(response.ID)))
+		if response.Error != nil {
+			call.Error = response.Error
+		} else if call.Result != nil && len(response.Result) > 0 {
+			if err = json.Unmarshal(response.Result, call.Result); err != nil {
+				call.Error = &DecodeError{Err: err, Body: response.Result, Method: call.Method, URL: redactURL(r.url)}
+			}
+		}
+	}
+	for _, call := range pending {
+		call.Error = fmt.Errorf("JSON-RPC 缺少响应: %s", call.Method)
+	}
+
+	for _, call := range calls {
+		if call.Error != nil {
+			rpcLogger.CWarnw(ctx, "JSON-RPC 调用失败", "url", r.url, "method", call.Method, "error", call.Error)
+		}
+	}
+	rpcLogger.CDebug(ctx, "JSON-RPC 调用 %s 完成, 调用数 %d, 耗时 %v", r.url, len(calls), time.Since(start))
+	return nil
+}
+
+func (r *RPCClient) decodeResponses(body []byte) ([]rpcResponse, error) {
+	body = bytes.TrimSpace(body)
+	var responses []rpcResponse
+	var err error
+	if len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &responses)
+	} else {
+		var response rpcResponse
+		if err = json.Unmarshal(body, &response); err == nil {
+			responses = []rpcResponse{response}
+		}
+	}
+	if err != nil {
+		decodeErr := &DecodeError{Err: err, Body: body, Method: http.MethodPost, URL: redactURL(r.url)}
+		if len(body) > maxDecodeErrorBodySize {
+			decodeErr.Body = body[:maxDecodeErrorBodySize]
+			decodeErr.Truncated = true
+		}
+		return nil, decodeErr
+	}
+	return responses, nil
+}
+
+// CallRPC 调用方法并将结果解码为 T
+// @param ctx context.Context 上下文
+// @param r *RPCClient 客户端
+// @param method string 方法名
+// @param params any 参数, nil 表示无参数
+func CallRPC[T any](ctx context.Context, r *RPCClient, method string, params any) (T, error) {
+	var result T
+	err := r.Call(ctx, method, params, &result)
+	return result, err
+}
+
+// RPCHandlerFunc JSON-RPC 方法, params 为原始参数, 无参数时为空
+// 返回 *RPCError 时原样响应, 其他错误只记录日志, 响应为不含错误内容的 RPCInternalError
+type RPCHandlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// RPCServer JSON-RPC 2.0 服务端, 按方法名分发请求
+type RPCServer struct {
+	mu          sync.RWMutex
+	methods     map[string]RPCHandlerFunc
+	maxBodySize int64
+}
+
+// NewRPCServer 创建 JSON-RPC 服务端, 请求体大小上限默认 DefaultMaxBodySize
+func NewRPCServer() *RPCServer {
+	return &RPCServer{methods: map[string]RPCHandlerFunc{}, maxBodySize: DefaultMaxBodySize}
+}
+
+// SetMaxBodySize 设置请求体大小上限, 超出时响应 413
+// @param size int64 字节数, 不大于 0 时使用 DefaultMaxBodySize
+func (s *RPCServer) SetMaxBodySize(size int64) *RPCServer {
+	if size <= 0 {
+		size = DefaultMaxBodySize
+	}
+	s.maxBodySize = size
+	return s
+}
+
+// Register 注册方法, 同名方法会被覆盖
+// @param method string 方法名
+// @param handler RPCHandlerFunc 处理函数
+func (s *RPCServer) Register(method string, handler RPCHandlerFunc) *RPCServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[method] = handler
+	return s
+}
+
+// RegisterRPC 注册参数和结果有类型的方法, 参数解码失败时响应 RPCInvalidParams
+// @param s *RPCServer 服务端
+// @param method string 方法名
+// @param handler func(ctx context.Context, params Req) (Resp, error) 处理函数
+func RegisterRPC[Req any, Resp any](s *RPCServer, method string, handler func(ctx context.Context, params Req) (Resp, error)) {
+	s.Register(method, func(ctx context.Context, raw json.RawMessage) (any, error) {
+		var params Req
+		if len(raw) > 0 {
+			if err := json.Unmarshal(raw, &params); err != nil {
+				return nil, NewRPCError(RPCInvalidParams, "Invalid params", err.Error())
+			}
+		}
+		return handler(ctx, params)
+	})
+}
+
+// Handler 处理 JSON-RPC 请求的 gin 处理函数, 支持批量请求, 只有通知时响应 204
+func (s *RPCServer) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		body, err := readBody(c, s.maxBodySize)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, errorResponse(nil, NewRPCError(RPCInvalidRequest, "Request too large", nil)))
+				return
+			}
+			c.JSON(http.StatusOK, errorResponse(nil, NewRPCError(RPCParseError, "Parse error", nil)))
+			return
+		}
+
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			var batch []json.RawMessage
+			if err = json.Unmarshal(body, &batch); err != nil {
+				c.JSON(http.StatusOK, errorResponse(nil, NewRPCError(RPCParseError, "Parse error", nil)))
+				return
+			}
+			if len(batch) == 0 {
+				c.JSON(http.StatusOK, errorResponse(nil, NewRPCError(RPCInvalidRequest, "Invalid Request", nil)))
+				return
+			}
+			responses := make([]*rpcResponse, 0, len(batch))
+			for _, raw := range batch {
+				if response := s.handle(ctx, raw); response != nil {
+					responses = append(responses, response)
+				}
+			}
+			if len(responses) == 0 {
+				c.Status(http.StatusNoContent)
+				return
+			}
+			c.JSON(http.StatusOK, responses)
+			return
+		}
+
+		if !json.Valid(body) {
+			c.JSON(http.StatusOK, errorResponse(nil, NewRPCError(RPCParseError, "Parse error", nil)))
+			return
+		}
+		response := s.handle(ctx, body)
+		if response == nil {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// handle 处理单个请求, 通知返回 nil
+func (s *RPCServer) handle(ctx context.Context, raw json.RawMessage) (response *rpcResponse) {
+	var request rpcRequest
+	if err := json.Unmarshal(raw, &request); err != nil || request.JSONRPC != JSONRPCVersion || request.Method == "" {
+		return errorResponse(request.ID, NewRPCError(RPCInvalidRequest, "Invalid Request", nil))
+	}
+	notify := request.ID == nil
+
+	s.mu.RLock()
+	handler, ok := s.methods[request.Method]
+	s.mu.RUnlock()
+	if !ok {
+		rpcLogger.CWarnw(ctx, "JSON-RPC 方法不存在", "method", request.Method)
+		if notify {
+			return nil
+		}
+		return errorResponse(request.ID, NewRPCError(RPCMethodNotFound, "Method not found", nil))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			rpcLogger.CErrorw(ctx, "JSON-RPC 方法异常", "method", request.Method, "panic", r)
+			response = nil
+			if !notify {
+				response = errorResponse(request.ID, NewRPCError(RPCInternalError, "Internal error", nil))
+			}
+		}
+	}()
+
+	start := time.Now()
+	result, err := handler(ctx, request.Params)
+	if err != nil {
+		var rpcErr *RPCError
+		if errors.As(err, &rpcErr) {
+			rpcLogger.CWarnw(ctx, "JSON-RPC 方法返回错误", "method", request.Method, "code", rpcErr.Code, "error", rpcErr.Message)
+		} else {
+			rpcLogger.CErrorw(ctx, "JSON-RPC 方法执行失败", "method", request.Method, "error", err)
+			// 错误内容可能包含 SQL、文件路径等内部信息, 只记录在服务端
+			rpcErr = NewRPCError(RPCInternalError, "Internal error", nil)
+		}
+		if notify {
+			return nil
+		}
+		return errorResponse(request.ID, rpcErr)
+	}
+	rpcLogger.CDebug(ctx, "JSON-RPC 方法 %s 完成, 耗时 %v", request.Method, time.Since(start))
+	if notify {
+		return nil
+	}
+
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		rpcLogger.CErrorw(ctx, "JSON-RPC 结果编码失败", "method", request.Method, "error", err)
+		return errorResponse(request.ID, NewRPCError(RPCInternalError, "Internal error", nil))
+	}
+	return &rpcResponse{JSONRPC: JSONRPCVersion, Result: resultData, ID: request.ID}
+}
+
+func errorResponse(id json.RawMessage, rpcErr *RPCError) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: JSONRPCVersion, Error: rpcErr, ID: id}
+}
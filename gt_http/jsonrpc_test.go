@@ -0,0 +1,167 @@
+package gt_http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type addParams struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+func newRPCTestServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	gin.SetMode(gin.TestMode)
+	var notified atomic.Int32
+	rpc := NewRPCServer()
+	RegisterRPC(rpc, "add", func(ctx context.Context, params addParams) (int, error) {
+		return params.A + params.B, nil
+	})
+	rpc.Register("fail", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return nil, NewRPCError(1001, "余额不足", map[string]int{"balance": 0})
+	})
+	rpc.Register("broken", func(ctx context.Context, params json.RawMessage) (any, error) {
+		return nil, errors.New("db down")
+	})
+	rpc.Register("panic", func(ctx context.Context, params json.RawMessage) (any, error) {
+		panic("boom")
+	})
+	rpc.Register("notify", func(ctx context.Context, params json.RawMessage) (any, error) {
+		notified.Add(1)
+		return nil, nil
+	})
+
+	engine := gin.New()
+	engine.POST("/rpc", rpc.Handler())
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return server, &notified
+}
+
+func TestRPCClient_Call(t *testing.T) {
+	server, notified := newRPCTestServer(t)
+	client := NewRPCClient(nil, server.URL+"/rpc", nil)
+	ctx := context.Background()
+
+	var sum int
+	assert.NoError(t, client.Call(ctx, "add", addParams{A: 1, B: 2}, &sum))
+	assert.Equal(t, 3, sum)
+
+	sum, err := CallRPC[int](ctx, client, "add", map[string]int{"a": 5, "b": 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 11, sum)
+
+	t.Run("typed error", func(t *testing.T) {
+		err := client.Call(ctx, "fail", nil, nil)
+		var rpcErr *RPCError
+		assert.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, 1001, rpcErr.Code)
+		assert.Equal(t, "余额不足", rpcErr.Message)
+		assert.JSONEq(t, `{"balance":0}`, string(rpcErr.Data))
+	})
+
+	t.Run("standard errors", func(t *testing.T) {
+		cases := map[string]int{
+			"missing": RPCMethodNotFound,
+			"broken":  RPCInternalError,
+			"panic":   RPCInternalError,
+		}
+		for method, code := range cases {
+			var rpcErr *RPCError
+			assert.True(t, errors.As(client.Call(ctx, method, nil, nil), &rpcErr), method)
+			assert.Equal(t, code, rpcErr.Code, method)
+		}
+
+		var rpcErr *RPCError
+		err := client.Call(ctx, "add", "not an object", nil)
+		assert.True(t, errors.As(err, &rpcErr))
+		assert.Equal(t, RPCInvalidParams, rpcErr.Code)
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		assert.NoError(t, client.Notify(ctx, "notify", nil))
+		assert.Equal(t, int32(1), notified.Load())
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		err := NewRPCClient(nil, server.URL+"/missing", nil).Call(ctx, "add", nil, nil)
+		assert.Equal(t, http.StatusNotFound, GetStatusCode(err))
+	})
+}
+
+func TestRPCClient_Batch(t *testing.T) {
+	server, notified := newRPCTestServer(t)
+	client := NewRPCClient(nil, server.URL+"/rpc", nil)
+	ctx := context.Background()
+
+	var a, b int
+	calls := []*RPCCall{
+		{Method: "add", Params: addParams{A: 1, B: 1}, Result: &a},
+		{Method: "fail"},
+		{Method: "notify", Notify: true},
+		{Method: "add", Params: addParams{A: 2, B: 3}, Result: &b},
+	}
+	assert.NoError(t, client.Batch(ctx, calls...))
+	assert.NoError(t, calls[0].Error)
+	assert.Equal(t, 2, a)
+	var rpcErr *RPCError
+	assert.True(t, errors.As(calls[1].Error, &rpcErr))
+	assert.NoError(t, calls[2].Error)
+	assert.NoError(t, calls[3].Error)
+	assert.Equal(t, 5, b)
+	assert.Equal(t, int32(1), notified.Load())
+
+	t.Run("only notifications", func(t *testing.T) {
+		assert.NoError(t, client.Batch(ctx, &RPCCall{Method: "notify", Notify: true}, &RPCCall{Method: "notify", Notify: true}))
+		assert.Equal(t, int32(3), notified.Load())
+	})
+}
+
+func TestRPCServer_Handler(t *testing.T) {
+	server, _ := newRPCTestServer(t)
+	post := func(body string) (int, string) {
+		response, err := http.Post(server.URL+"/rpc", ContentTypeJSON, strings.NewReader(body))
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		return response.StatusCode, string(data)
+	}
+
+	status, body := post(`{"jsonrpc":"2.0","method":"add","params":{"a":1,"b":2},"id":"abc"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","result":3,"id":"abc"}`, body)
+
+	_, body = post(`{"jsonrpc":"2.0","method":`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"},"id":null}`, body)
+
+	_, body = post(`[]`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null}`, body)
+
+	_, body = post(`{"jsonrpc":"1.0","method":"add","id":1}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":1}`, body)
+
+	_, body = post(`[1,{"jsonrpc":"2.0","method":"add","params":{"a":2,"b":2},"id":2}]`)
+	assert.JSONEq(t, `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"},"id":null},{"jsonrpc":"2.0","result":4,"id":2}]`, body)
+
+	status, body = post(`{"jsonrpc":"2.0","method":"missing"}`)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Empty(t, body)
+
+	// 内部错误内容不返回给调用方
+	_, body = post(`{"jsonrpc":"2.0","method":"broken","id":3}`)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":3}`, body)
+
+	status, body = post(`{"jsonrpc":"2.0","method":"add","params":"` + strings.Repeat("x", DefaultMaxBodySize) + `","id":4}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Contains(t, body, "Request too large")
+}
@@ -0,0 +1,204 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited 快速失败模式下超出限流返回的错误
+var ErrRateLimited = errors.New("请求被限流")
+
+// LimiterConfig 限流配置, 按 key 分别限制, 默认 key 为请求的 host
+type LimiterConfig struct {
+	QPS         float64 `mapstructure:"qps"`           // 每秒请求数(令牌桶速率), 0 表示不限速
+	Burst       int     `mapstructure:"burst"`         // 令牌桶容量, 默认为 QPS 向上取整且不小于 1
+	MaxInFlight int     `mapstructure:"max_in_flight"` // 最大并发请求数, 0 表示不限制
+	FailFast    bool    `mapstructure:"fail_fast"`     // true 时超出限制立即返回 ErrRateLimited, 否则阻塞等待直到 ctx 结束
+
+	// KeyFunc 自定义限流 key, 默认使用 WithLimitKey 设置的 key, 未设置时使用 host
+	KeyFunc func(request *http.Request) string `mapstructure:"-"`
+}
+
+type limitKeyCtxKey struct{}
+
+// WithLimitKey 设置请求使用的限流 key, 用于多个 host 共享同一限额等场景
+// @param ctx context.Context 上下文
+// @param key string 限流 key
+func WithLimitKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, limitKeyCtxKey{}, key)
+}
+
+// Limiter 按 key 分组的令牌桶限流和并发限制
+type Limiter struct {
+	config  LimiterConfig
+	mu      sync.Mutex
+	entries map[string]*limiterEntry
+}
+
+// NewLimiter 创建限流器
+// @param config LimiterConfig 限流配置
+func NewLimiter(config LimiterConfig) *Limiter {
+	if config.QPS > 0 && config.Burst <= 0 {
+		config.Burst = max(1, int(math.Ceil(config.QPS)))
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = defaultLimitKey
+	}
+	return &Limiter{
+		config:  config,
+		entries: map[string]*limiterEntry{},
+	}
+}
+
+func defaultLimitKey(request *http.Request) string {
+	if key, ok := request.Context().Value(limitKeyCtxKey{}).(string); ok && key != "" {
+		return key
+	}
+	return request.URL.Host
+}
+
+// Middleware 限流中间件, 并发名额在响应体关闭时释放
+func (l *Limiter) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			release, err := l.Acquire(request.Context(), l.config.KeyFunc(request))
+			if err != nil {
+				return nil, err
+			}
+			response, err := next.RoundTrip(request)
+			if err != nil {
+				release()
+				return response, err
+			}
+			response.Body = &releaseBody{ReadCloser: response.Body, release: release}
+			return response, nil
+		})
+	}
+}
+
+// Acquire 获取一次请求的限额, 成功后须调用 release 释放并发名额
+// @param ctx context.Context 上下文, 阻塞模式下 ctx 结束时返回其错误
+// @param key string 限流 key
+func (l *Limiter) Acquire(ctx context.Context, key string) (release func(), err error) {
+	entry := l.get(key)
+	if entry.inFlight != nil {
+		if l.config.FailFast {
+			select {
+			case entry.inFlight <- struct{}{}:
+			default:
+				return nil, fmt.Errorf("%w: %s 并发数超过 %d", ErrRateLimited, key, l.config.MaxInFlight)
+			}
+		} else {
+			select {
+			case entry.inFlight <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	release = entry.release
+
+	if entry.bucket != nil {
+		if err = entry.bucket.wait(ctx, l.config.FailFast); err != nil {
+			release()
+			if errors.Is(err, ErrRateLimited) {
+				err = fmt.Errorf("%w: %s QPS 超过 %v", ErrRateLimited, key, l.config.QPS)
+			}
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+func (l *Limiter) get(key string) *limiterEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, ok := l.entries[key]
+	if !ok {
+		entry = &limiterEntry{}
+		if l.config.QPS > 0 {
+			entry.bucket = newTokenBucket(l.config.QPS, l.config.Burst)
+		}
+		if l.config.MaxInFlight > 0 {
+			entry.inFlight = make(chan struct{}, l.config.MaxInFlight)
+		}
+		l.entries[key] = entry
+	}
+	return entry
+}
+
+type limiterEntry struct {
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+func (e *limiterEntry) release() {
+	if e.inFlight != nil {
+		<-e.inFlight
+	}
+}
+
+// tokenBucket 令牌桶, 令牌可透支以实现排队等待
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context, failFast bool) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		b.mu.Unlock()
+		return nil
+	}
+	if failFast {
+		b.mu.Unlock()
+		return ErrRateLimited
+	}
+	// 预占令牌后等待令牌补足
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	b.tokens--
+	b.mu.Unlock()
+
+	if err := sleepContext(ctx, wait); err != nil {
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// releaseBody 响应体关闭时释放限额
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,99 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_QPS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	t.Run("blocking", func(t *testing.T) {
+		limiter := NewLimiter(LimiterConfig{QPS: 20, Burst: 1})
+		client := NewClient(DefaultClientConfig).Use(limiter.Middleware())
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			_, err := client.Get(server.URL, nil)
+			assert.NoError(t, err)
+		}
+		// 首个请求消耗初始令牌, 之后每个请求等待 50ms
+		assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		limiter := NewLimiter(LimiterConfig{QPS: 1, Burst: 2, FailFast: true})
+		client := NewClient(DefaultClientConfig).Use(limiter.Middleware())
+		for i := 0; i < 2; i++ {
+			_, err := client.Get(server.URL, nil)
+			assert.NoError(t, err)
+		}
+		_, err := client.Get(server.URL, nil)
+		assert.True(t, errors.Is(err, ErrRateLimited))
+	})
+
+	t.Run("context cancel while waiting", func(t *testing.T) {
+		limiter := NewLimiter(LimiterConfig{QPS: 1, Burst: 1})
+		release, err := limiter.Acquire(context.Background(), "k")
+		assert.NoError(t, err)
+		release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = limiter.Acquire(ctx, "k")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		// 其它 key 不受影响
+		_, err = limiter.Acquire(context.Background(), "other")
+		assert.NoError(t, err)
+	})
+}
+
+func TestLimiter_MaxInFlight(t *testing.T) {
+	var current, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(LimiterConfig{MaxInFlight: 2})
+	client := NewClient(DefaultClientConfig).Use(limiter.Middleware())
+	ctx := WithLimitKey(context.Background(), "partner")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetCtx(ctx, server.URL, nil)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+	fastLimiter := NewLimiter(LimiterConfig{MaxInFlight: 1, FailFast: true})
+	release, err := fastLimiter.Acquire(ctx, "partner")
+	assert.NoError(t, err)
+	_, err = fastLimiter.Acquire(ctx, "partner")
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	release()
+	_, err = fastLimiter.Acquire(ctx, "partner")
+	assert.NoError(t, err)
+}
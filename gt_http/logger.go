@@ -0,0 +1,8 @@
+package gt_http
+
+import "github.com/INT-Game/go-tools/slog"
+
+var (
+	httpLogger = slog.NewSLogger("[http] %s")
+	rpcLogger  = slog.NewSLogger("[jsonrpc] %s")
+)
@@ -0,0 +1,200 @@
+package gt_http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggingConfig 请求日志中间件配置
+type LoggingConfig struct {
+	LogBody      bool     `mapstructure:"log_body"`      // 是否记录请求体和响应体
+	MaxBodySize  int      `mapstructure:"max_body_size"` // 记录的请求体/响应体最大字节数, 默认 1024
+	RedactFields []string `mapstructure:"redact_fields"` // 需要打码的字段名(JSON、表单和查询参数), 为空时使用 DefaultRedactFields
+}
+
+// DefaultRedactFields 默认打码的字段
+var DefaultRedactFields = []string{"password", "passwd", "secret", "token", "access_token", "sign", "signature", "key"}
+
+const redactedValue = "***"
+
+// NewLoggingMiddleware 创建请求日志中间件, 通过 slog 的 context logger 记录
+// 请求方法、地址、状态码、耗时、请求/响应大小和可选的请求/响应体, 日志带有 ctx 中的追踪字段
+// 响应日志在响应体被关闭时输出, 以便记录完整的响应大小和耗时
+// @param config LoggingConfig 日志配置
+func NewLoggingMiddleware(config LoggingConfig) Middleware {
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 1024
+	}
+	fields := config.RedactFields
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+	redactor := newRedactor(fields)
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			entry := &requestLog{
+				config:   config,
+				redactor: redactor,
+				request:  request,
+				start:    time.Now(),
+			}
+			if config.LogBody {
+				entry.reqBody = readRequestBody(request, config.MaxBodySize)
+			}
+
+			response, err := next.RoundTrip(request)
+			if err != nil {
+				entry.log(nil, err)
+				return response, err
+			}
+
+			entry.response = response
+			response.Body = &loggingBody{ReadCloser: response.Body, entry: entry}
+			return response, nil
+		})
+	}
+}
+
+type requestLog struct {
+	config   LoggingConfig
+	redactor *redactor
+	request  *http.Request
+	response *http.Response
+	start    time.Time
+	reqBody  []byte
+	respBody bytes.Buffer
+	respSize int64
+	once     sync.Once
+}
+
+func (e *requestLog) log(readErr error, err error) {
+	e.once.Do(func() {
+		request := e.request
+		fields := []any{
+			"method", request.Method,
+			"url", e.redactor.redactURL(request),
+			"latency", time.Since(e.start),
+			"req_size", request.ContentLength,
+		}
+		if e.config.LogBody && len(e.reqBody) > 0 {
+			fields = append(fields, "req_body", e.redactor.redactBody(e.reqBody, e.config.MaxBodySize))
+		}
+
+		level := zapcore.InfoLevel
+		if e.response != nil {
+			fields = append(fields, "status", e.response.StatusCode, "resp_size", e.respSize)
+			if e.config.LogBody && e.respBody.Len() > 0 {
+				fields = append(fields, "resp_body", e.redactor.redactBody(e.respBody.Bytes(), e.config.MaxBodySize))
+			}
+			if e.response.StatusCode >= http.StatusInternalServerError {
+				level = zapcore.ErrorLevel
+			} else if e.response.StatusCode >= http.StatusBadRequest {
+				level = zapcore.WarnLevel
+			}
+		}
+		if err == nil {
+			err = readErr
+		}
+		if err != nil {
+			fields = append(fields, "error", err.Error())
+			level = zapcore.ErrorLevel
+		}
+
+		msg := fmt.Sprintf("%s %s", request.Method, request.URL.Path)
+		httpLogger.CLogw(request.Context(), level, 0, msg, fields...)
+	})
+}
+
+// loggingBody 记录读取的响应体大小和前 MaxBodySize 字节, 关闭时输出日志
+type loggingBody struct {
+	io.ReadCloser
+	entry   *requestLog
+	readErr error
+}
+
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	entry := b.entry
+	entry.respSize += int64(n)
+	if entry.config.LogBody {
+		if remain := entry.config.MaxBodySize + 1 - entry.respBody.Len(); remain > 0 {
+			entry.respBody.Write(p[:min(n, remain)])
+		}
+	}
+	if err != nil && err != io.EOF {
+		b.readErr = err
+	}
+	return n, err
+}
+
+func (b *loggingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.entry.log(b.readErr, nil)
+	return err
+}
+
+// readRequestBody 通过 GetBody 读取请求体副本, 不影响实际发送
+func readRequestBody(request *http.Request, maxBodySize int) []byte {
+	if request.GetBody == nil || request.Body == nil || request.Body == http.NoBody {
+		return nil
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, _ := io.ReadAll(io.LimitReader(body, int64(maxBodySize)+1))
+	return data
+}
+
+// redactor 对JSON、表单和查询参数中的敏感字段打码
+type redactor struct {
+	jsonPattern *regexp.Regexp
+	formPattern *regexp.Regexp
+}
+
+func newRedactor(fields []string) *redactor {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+	names := strings.Join(quoted, "|")
+	return &redactor{
+		jsonPattern: regexp.MustCompile(`(?i)("(?:` + names + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`),
+		formPattern: regexp.MustCompile(`(?i)((?:^|&)(?:` + names + `)=)[^&]*`),
+	}
+}
+
+func (r *redactor) redact(s string) string {
+	s = r.jsonPattern.ReplaceAllString(s, `${1}"`+redactedValue+`"`)
+	return r.formPattern.ReplaceAllString(s, "${1}"+redactedValue)
+}
+
+func (r *redactor) redactBody(body []byte, maxBodySize int) string {
+	truncated := len(body) > maxBodySize
+	if truncated {
+		body = body[:maxBodySize]
+	}
+	s := r.redact(string(body))
+	if truncated {
+		s += "..."
+	}
+	return s
+}
+
+func (r *redactor) redactURL(request *http.Request) string {
+	u := *request.URL
+	u.RawQuery = r.redact(u.RawQuery)
+	return u.Redacted()
+}
@@ -0,0 +1,72 @@
+package gt_http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/INT-Game/go-tools/slog/log_context"
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	loggers.Logger_2 = zap.New(core).Sugar()
+	t.Cleanup(func() {
+		loggers.Logger_2 = nil
+	})
+	return logs
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	logs := observeLogs(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+		data, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	client := NewClient(DefaultClientConfig).Use(NewLoggingMiddleware(LoggingConfig{LogBody: true, MaxBodySize: 64}))
+	ctx := log_context.SetTrackLogContext(context.Background(), "req-1", "tra-1")
+
+	_, err := client.NewRequest(http.MethodPost, server.URL+"/login?token=abc&a=1").
+		SetJSONBody(map[string]string{"user": "u", "password": "p"}).
+		Do(ctx)
+	assert.NoError(t, err)
+
+	_, err = client.PostCtx(ctx, server.URL+"/fail", nil, map[string]string{"a": "b"})
+	assert.Error(t, err)
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 2)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "[http] POST /login", entries[0].Message)
+	assert.Equal(t, "req-1", fields[log_context.CtxRequestId])
+	assert.Equal(t, "tra-1", fields[log_context.CtxTraceId])
+	assert.Equal(t, int64(http.StatusOK), fields["status"])
+	assert.Equal(t, server.URL+"/login?token=***&a=1", fields["url"])
+	assert.Equal(t, `{"password":"***","user":"u"}`, fields["req_body"])
+	assert.Equal(t, `{"password":"***","user":"u"}`, fields["resp_body"])
+	assert.Equal(t, int64(27), fields["resp_size"])
+
+	assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	assert.Equal(t, int64(http.StatusBadGateway), entries[1].ContextMap()["status"])
+}
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor(DefaultRedactFields)
+	assert.Equal(t, `{"Token":"***","n":1,"sign":"***"}`, r.redact(`{"Token":"a\"b","n":1,"sign":123}`))
+	assert.Equal(t, `a=1&password=***&b=2`, r.redact(`a=1&password=xx&b=2`))
+	assert.Equal(t, `{"secret":"***"...`, r.redactBody([]byte(`{"secret":"abcdefghijk"}`), 15))
+}
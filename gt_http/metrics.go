@@ -0,0 +1,249 @@
+package gt_http
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsHook 请求指标回调, 实现须并发安全
+type MetricsHook interface {
+	// OnRequestStart 请求开始发送
+	OnRequestStart(request *http.Request)
+	// OnRequestDone 请求结束: 响应体关闭或请求出错; 请求出错时 status 为 0
+	OnRequestDone(request *http.Request, status int, latency time.Duration, err error)
+}
+
+// NewMetricsMiddleware 创建请求指标中间件, 耗时包含读取响应体的时间
+// @param hook MetricsHook 指标回调
+func NewMetricsMiddleware(hook MetricsHook) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			start := time.Now()
+			hook.OnRequestStart(request)
+			response, err := next.RoundTrip(request)
+			if err != nil {
+				hook.OnRequestDone(request, 0, time.Since(start), err)
+				return response, err
+			}
+			response.Body = &releaseBody{ReadCloser: response.Body, release: func() {
+				hook.OnRequestDone(request, response.StatusCode, time.Since(start), nil)
+			}}
+			return response, nil
+		})
+	}
+}
+
+// DefaultLatencyBuckets 默认耗时直方图分桶(秒)
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricsConfig 内存指标配置
+type MetricsConfig struct {
+	Namespace string    `mapstructure:"namespace"` // 指标名前缀, 默认 gt_http_client
+	Buckets   []float64 `mapstructure:"buckets"`   // 耗时直方图分桶(秒), 须升序, 默认 DefaultLatencyBuckets
+}
+
+// Metrics 按 host 统计的内存请求指标: 耗时直方图、状态码计数、错误计数和进行中的请求数
+type Metrics struct {
+	config MetricsConfig
+	mu     sync.Mutex
+	hosts  map[string]*hostMetrics
+}
+
+// HostMetrics 单个 host 的指标快照
+type HostMetrics struct {
+	InFlight     int64          // 进行中的请求数
+	Count        uint64         // 已完成的请求数(含出错)
+	Sum          time.Duration  // 已完成请求的总耗时
+	BucketCounts []uint64       // 各分桶的累计请求数, 与 MetricsConfig.Buckets 对应
+	StatusCounts map[int]uint64 // 各状态码的请求数
+	Errors       uint64         // 出错的请求数
+}
+
+type hostMetrics struct {
+	inFlight     int64
+	count        uint64
+	sum          time.Duration
+	bucketCounts []uint64
+	statusCounts map[int]uint64
+	errors       uint64
+}
+
+// NewMetrics 创建内存指标
+// @param config MetricsConfig 指标配置
+func NewMetrics(config MetricsConfig) *Metrics {
+	if config.Namespace == "" {
+		config.Namespace = "gt_http_client"
+	}
+	if len(config.Buckets) == 0 {
+		config.Buckets = DefaultLatencyBuckets
+	}
+	return &Metrics{
+		config: config,
+		hosts:  map[string]*hostMetrics{},
+	}
+}
+
+// Middleware 统计指标的中间件
+func (m *Metrics) Middleware() Middleware {
+	return NewMetricsMiddleware(m)
+}
+
+// OnRequestStart 实现 MetricsHook
+func (m *Metrics) OnRequestStart(request *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.host(request.URL.Host).inFlight++
+}
+
+// OnRequestDone 实现 MetricsHook
+func (m *Metrics) OnRequestDone(request *http.Request, status int, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h := m.host(request.URL.Host)
+	h.inFlight--
+	h.count++
+	h.sum += latency
+	seconds := latency.Seconds()
+	for i, bound := range m.config.Buckets {
+		if seconds <= bound {
+			h.bucketCounts[i]++
+		}
+	}
+	if err != nil {
+		h.errors++
+		return
+	}
+	h.statusCounts[status]++
+}
+
+// Snapshot 获取各 host 的指标快照
+func (m *Metrics) Snapshot() map[string]HostMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]HostMetrics, len(m.hosts))
+	for host, h := range m.hosts {
+		statusCounts := make(map[int]uint64, len(h.statusCounts))
+		for status, count := range h.statusCounts {
+			statusCounts[status] = count
+		}
+		snapshot[host] = HostMetrics{
+			InFlight:     h.inFlight,
+			Count:        h.count,
+			Sum:          h.sum,
+			BucketCounts: append([]uint64(nil), h.bucketCounts...),
+			StatusCounts: statusCounts,
+			Errors:       h.errors,
+		}
+	}
+	return snapshot
+}
+
+// Reset 清空计数, 进行中的请求数保留
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for host, h := range m.hosts {
+		if h.inFlight == 0 {
+			delete(m.hosts, host)
+			continue
+		}
+		m.hosts[host] = &hostMetrics{
+			inFlight:     h.inFlight,
+			bucketCounts: make([]uint64, len(m.config.Buckets)),
+			statusCounts: map[int]uint64{},
+		}
+	}
+}
+
+// WritePrometheus 以 Prometheus 文本格式输出指标
+// @param w io.Writer 输出
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+	hosts := make([]string, 0, len(snapshot))
+	for host := range snapshot {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	ns := m.config.Namespace
+	buf := bufio.NewWriter(w)
+
+	name := ns + "_request_duration_seconds"
+	fmt.Fprintf(buf, "# HELP %s HTTP client request latency in seconds.\n# TYPE %s histogram\n", name, name)
+	for _, host := range hosts {
+		h := snapshot[host]
+		label := "host=" + quoteLabel(host)
+		for i, bound := range m.config.Buckets {
+			fmt.Fprintf(buf, "%s_bucket{%s,le=\"%s\"} %d\n", name, label, formatFloat(bound), h.BucketCounts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, label, h.Count)
+		fmt.Fprintf(buf, "%s_sum{%s} %s\n", name, label, formatFloat(h.Sum.Seconds()))
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", name, label, h.Count)
+	}
+
+	name = ns + "_requests_total"
+	fmt.Fprintf(buf, "# HELP %s HTTP client responses by status code.\n# TYPE %s counter\n", name, name)
+	for _, host := range hosts {
+		h := snapshot[host]
+		statuses := make([]int, 0, len(h.StatusCounts))
+		for status := range h.StatusCounts {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(buf, "%s{host=%s,code=\"%d\"} %d\n", name, quoteLabel(host), status, h.StatusCounts[status])
+		}
+	}
+
+	name = ns + "_request_errors_total"
+	fmt.Fprintf(buf, "# HELP %s HTTP client requests failed without a response.\n# TYPE %s counter\n", name, name)
+	for _, host := range hosts {
+		fmt.Fprintf(buf, "%s{host=%s} %d\n", name, quoteLabel(host), snapshot[host].Errors)
+	}
+
+	name = ns + "_in_flight_requests"
+	fmt.Fprintf(buf, "# HELP %s HTTP client requests in flight.\n# TYPE %s gauge\n", name, name)
+	for _, host := range hosts {
+		fmt.Fprintf(buf, "%s{host=%s} %d\n", name, quoteLabel(host), snapshot[host].InFlight)
+	}
+	return buf.Flush()
+}
+
+// Handler 输出 Prometheus 文本格式指标的 http.Handler, gin 中可通过 gin.WrapH 挂载
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := m.WritePrometheus(w); err != nil {
+			httpLogger.CWarn(r.Context(), "输出指标失败: %v", err)
+		}
+	})
+}
+
+func (m *Metrics) host(host string) *hostMetrics {
+	h, ok := m.hosts[host]
+	if !ok {
+		h = &hostMetrics{
+			bucketCounts: make([]uint64, len(m.config.Buckets)),
+			statusCounts: map[int]uint64{},
+		}
+		m.hosts[host] = h
+	}
+	return h
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
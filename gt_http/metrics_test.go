@@ -0,0 +1,83 @@
+package gt_http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	host := mustParseURL(t, server.URL).Host
+
+	metrics := NewMetrics(MetricsConfig{Buckets: []float64{0.01, 1}})
+	client := NewClient(DefaultClientConfig).Use(metrics.Middleware())
+
+	_, err := client.Get(server.URL, nil)
+	assert.NoError(t, err)
+	_, err = client.Get(server.URL+"/slow", nil)
+	assert.NoError(t, err)
+	_, err = client.Get(server.URL+"/missing", nil)
+	assert.Equal(t, http.StatusNotFound, GetStatusCode(err))
+	_, err = client.Get("http://127.0.0.1:1", nil)
+	assert.Error(t, err)
+
+	snapshot := metrics.Snapshot()
+	h := snapshot[host]
+	assert.Equal(t, uint64(3), h.Count)
+	assert.Equal(t, int64(0), h.InFlight)
+	assert.Equal(t, map[int]uint64{200: 2, 404: 1}, h.StatusCounts)
+	assert.Equal(t, uint64(3), h.BucketCounts[1])
+	assert.Less(t, h.BucketCounts[0], uint64(3))
+	assert.GreaterOrEqual(t, h.Sum, 30*time.Millisecond)
+	assert.Equal(t, uint64(1), snapshot["127.0.0.1:1"].Errors)
+
+	t.Run("in flight", func(t *testing.T) {
+		stream, err := client.NewRequest(http.MethodGet, server.URL).Stream(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), metrics.Snapshot()[host].InFlight)
+		_ = stream.Close()
+		assert.Equal(t, int64(0), metrics.Snapshot()[host].InFlight)
+	})
+
+	t.Run("prometheus", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, _ := io.ReadAll(recorder.Body)
+		text := string(body)
+		assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+		assert.Contains(t, text, "# TYPE gt_http_client_request_duration_seconds histogram")
+		assert.Contains(t, text, `gt_http_client_request_duration_seconds_bucket{host="`+host+`",le="1"} 4`)
+		assert.Contains(t, text, `gt_http_client_request_duration_seconds_bucket{host="`+host+`",le="+Inf"} 4`)
+		assert.Contains(t, text, `gt_http_client_request_duration_seconds_count{host="`+host+`"} 4`)
+		assert.Contains(t, text, `gt_http_client_requests_total{host="`+host+`",code="200"} 3`)
+		assert.Contains(t, text, `gt_http_client_requests_total{host="`+host+`",code="404"} 1`)
+		assert.Contains(t, text, `gt_http_client_request_errors_total{host="127.0.0.1:1"} 1`)
+		assert.Contains(t, text, `gt_http_client_in_flight_requests{host="`+host+`"} 0`)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		metrics.Reset()
+		assert.Empty(t, metrics.Snapshot())
+	})
+}
+
+func TestQuoteLabel(t *testing.T) {
+	assert.Equal(t, `"a\\b\"c\nd"`, quoteLabel("a\\b\"c\nd"))
+}
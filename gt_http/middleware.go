@@ -0,0 +1,32 @@
+package gt_http
+
+import "net/http"
+
+// Middleware 包装 http.RoundTripper 的中间件, 每次实际发送(含重试)都会经过
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc 函数形式的 http.RoundTripper
+type RoundTripperFunc func(request *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// Use 添加中间件, 先添加的在外层; 应在客户端开始使用前调用
+// @param middlewares ...Middleware 中间件
+func (c *Client) Use(middlewares ...Middleware) *Client {
+	c.middlewares = append(c.middlewares, middlewares...)
+	var roundTripper http.RoundTripper = c.transport
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		roundTripper = c.middlewares[i](roundTripper)
+	}
+	c.httpClient.Transport = roundTripper
+	return c
+}
+
+// closeRequestBody 关闭请求体, 中间件不把原请求交给下一层 (直接返回错误或发送副本) 时调用, 遵循 http.RoundTripper 的约定
+func closeRequestBody(request *http.Request) {
+	if request.Body != nil {
+		_ = request.Body.Close()
+	}
+}
@@ -0,0 +1,265 @@
+package gt_http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+const (
+	ContentTypeJSON      = "application/json"
+	ContentTypeForm      = "application/x-www-form-urlencoded"
+	ContentTypeOctet     = "application/octet-stream"
+	ContentTypeMultipart = "multipart/form-data"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// FormFile multipart 上传的文件
+type FormFile struct {
+	FieldName   string    // 表单字段名
+	FileName    string    // 文件名
+	ContentType string    // 文件类型, 默认 application/octet-stream
+	Reader      io.Reader // 文件内容
+}
+
+// Request 请求构造器, 支持任意请求方法、查询参数和多种请求体编码
+// 请求头与 HttpGet/HttpPost 的 header 参数语义一致: 同名请求头后设置的覆盖先设置的
+type Request struct {
+	client      *Client
+	method      string
+	url         string
+	header      map[string]string
+	query       url.Values
+	body        []byte
+	contentType string
+	err         error
+}
+
+// NewRequest 使用默认客户端创建请求构造器
+// @param method string 请求方法
+// @param url string 请求地址
+func NewRequest(method string, url string) *Request {
+	return DefaultClient.NewRequest(method, url)
+}
+
+// NewRequest 创建请求构造器
+// @param method string 请求方法
+// @param url string 请求地址
+func (c *Client) NewRequest(method string, url string) *Request {
+	return &Request{
+		client: c,
+		method: method,
+		url:    url,
+		header: map[string]string{},
+		query:  map[string][]string{},
+	}
+}
+
+// SetHeader 设置请求头
+func (r *Request) SetHeader(key string, value string) *Request {
+	r.header[key] = value
+	return r
+}
+
+// SetHeaders 批量设置请求头
+func (r *Request) SetHeaders(header map[string]string) *Request {
+	for k, v := range header {
+		r.header[k] = v
+	}
+	return r
+}
+
+// SetQuery 设置查询参数, 覆盖同名参数
+func (r *Request) SetQuery(key string, value string) *Request {
+	r.query.Set(key, value)
+	return r
+}
+
+// AddQuery 追加查询参数
+func (r *Request) AddQuery(key string, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// SetQueries 批量设置查询参数
+func (r *Request) SetQueries(query map[string]string) *Request {
+	for k, v := range query {
+		r.query.Set(k, v)
+	}
+	return r
+}
+
+// SetJSONBody 设置JSON请求体
+// @param data any 请求数据
+func (r *Request) SetJSONBody(data any) *Request {
+	body, err := json.Marshal(data)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	return r.SetRawBody(ContentTypeJSON, body)
+}
+
+// SetFormBody 设置 application/x-www-form-urlencoded 请求体
+// @param form map[string]string 表单数据
+func (r *Request) SetFormBody(form map[string]string) *Request {
+	values := url.Values{}
+	for k, v := range form {
+		values.Set(k, v)
+	}
+	return r.SetFormValues(values)
+}
+
+// SetFormValues 设置 application/x-www-form-urlencoded 请求体, 支持同名多值
+// @param values url.Values 表单数据
+func (r *Request) SetFormValues(values url.Values) *Request {
+	return r.SetRawBody(ContentTypeForm, []byte(values.Encode()))
+}
+
+// SetMultipartBody 设置 multipart/form-data 请求体
+// 文件内容会被读入内存, 以便重试时重放请求体
+// @param fields map[string]string 普通表单字段
+// @param files ...*FormFile 上传的文件
+func (r *Request) SetMultipartBody(fields map[string]string, files ...*FormFile) *Request {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	for k, v := range fields {
+		if err := writer.WriteField(k, v); err != nil {
+			r.err = err
+			return r
+		}
+	}
+	for _, file := range files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = ContentTypeOctet
+		}
+		partHeader := textproto.MIMEHeader{}
+		partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(file.FileName)))
+		partHeader.Set("Content-Type", contentType)
+		part, err := writer.CreatePart(partHeader)
+		if err != nil {
+			r.err = err
+			return r
+		}
+		if _, err = io.Copy(part, file.Reader); err != nil {
+			r.err = err
+			return r
+		}
+	}
+	if err := writer.Close(); err != nil {
+		r.err = err
+		return r
+	}
+	return r.SetRawBody(writer.FormDataContentType(), buf.Bytes())
+}
+
+// SetRawBody 设置原始请求体
+// @param contentType string 请求体类型, 为空时不设置 Content-Type
+// @param body []byte 请求体
+func (r *Request) SetRawBody(contentType string, body []byte) *Request {
+	r.contentType = contentType
+	r.body = body
+	if r.body == nil {
+		r.body = []byte{}
+	}
+	return r
+}
+
+// Build 生成 *http.Request
+// @param ctx context.Context 上下文
+func (r *Request) Build(ctx context.Context) (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	reqURL := r.url
+	if len(r.query) > 0 {
+		u, err := url.Parse(r.url)
+		if err != nil {
+			return nil, err
+		}
+		query := u.Query()
+		for k, v := range r.query {
+			query[k] = v
+		}
+		u.RawQuery = query.Encode()
+		reqURL = u.String()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	request, err := http.NewRequestWithContext(ctx, strings.ToUpper(r.method), reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.body != nil && r.contentType != "" {
+		request.Header.Set("Content-Type", r.contentType)
+	}
+
+	for k, v := range r.header {
+		request.Header.Set(k, v)
+	}
+	return request, nil
+}
+
+// Send 发送请求并返回原始响应, 不检查状态码
+// 不应用客户端的 Timeout, 超时由 ctx 控制; 调用方负责关闭返回的 response.Body
+// @param ctx context.Context 上下文
+func (r *Request) Send(ctx context.Context) (*http.Response, error) {
+	request, err := r.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.Do(request)
+}
+
+// Do 发送请求并读取全部响应体, 状态码不被接受时返回 *StatusError
+// 客户端的 Timeout 覆盖整个调用, 包括所有重试和读取响应体
+// @param ctx context.Context 上下文
+func (r *Request) Do(ctx context.Context) ([]byte, error) {
+	var respBody []byte
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r.client.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.client.config.Timeout)
+		defer cancel()
+	}
+
+	response, err := r.Send(ctx)
+	if err != nil {
+		return respBody, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if err = r.client.checkStatus(response); err != nil {
+		return respBody, err
+	}
+
+	respBody, err = io.ReadAll(response.Body)
+	if err != nil {
+		return respBody, err
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,90 @@
+package gt_http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Query", r.URL.RawQuery)
+		if strings.HasPrefix(r.Header.Get("Content-Type"), ContentTypeMultipart) {
+			_ = r.ParseMultipartForm(1 << 20)
+			file, header, err := r.FormFile("file")
+			assert.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			_, _ = w.Write([]byte(r.FormValue("name") + ":" + header.Filename + ":" + string(data)))
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("get has no content type", func(t *testing.T) {
+		response, err := NewRequest(http.MethodGet, server.URL+"?a=1").SetQuery("b", "2").AddQuery("b", "3").Send(ctx)
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, "GET", response.Header.Get("X-Method"))
+		assert.Equal(t, "", response.Header.Get("X-Content-Type"))
+		assert.Equal(t, "a=1&b=2&b=3", response.Header.Get("X-Query"))
+	})
+
+	t.Run("form body", func(t *testing.T) {
+		body, err := NewRequest(http.MethodPost, server.URL).SetFormBody(map[string]string{"a": "1", "b": "x y"}).Do(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "a=1&b=x+y", string(body))
+	})
+
+	t.Run("raw body with header override", func(t *testing.T) {
+		response, err := NewRequest(http.MethodPut, server.URL).
+			SetRawBody(ContentTypeOctet, []byte{1, 2, 3}).
+			SetHeaders(map[string]string{"Content-Type": "application/x-custom"}).
+			Send(ctx)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(response.Body)
+		_ = response.Body.Close()
+		assert.Equal(t, []byte{1, 2, 3}, data)
+		assert.Equal(t, "PUT", response.Header.Get("X-Method"))
+		assert.Equal(t, "application/x-custom", response.Header.Get("X-Content-Type"))
+	})
+
+	t.Run("json body", func(t *testing.T) {
+		body, err := NewRequest(http.MethodPatch, server.URL).SetJSONBody(map[string]int{"a": 1}).Do(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":1}`, string(body))
+	})
+
+	t.Run("json encode error", func(t *testing.T) {
+		_, err := NewRequest(http.MethodPost, server.URL).SetJSONBody(make(chan int)).Do(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("multipart body", func(t *testing.T) {
+		body, err := NewRequest(http.MethodPost, server.URL).
+			SetMultipartBody(map[string]string{"name": "bundle"}, &FormFile{
+				FieldName: "file",
+				FileName:  "a.txt",
+				Reader:    strings.NewReader("content"),
+			}).
+			Do(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "bundle:a.txt:content", string(body))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		response, err := NewRequest(http.MethodDelete, server.URL).Send(ctx)
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+		assert.Equal(t, "DELETE", response.Header.Get("X-Method"))
+	})
+}
@@ -0,0 +1,115 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig 重试策略, 使用带抖动的指数退避
+type RetryConfig struct {
+	MaxAttempts        int           `mapstructure:"max_attempts"`         // 最大尝试次数(含首次)
+	InitialBackoff     time.Duration `mapstructure:"initial_backoff"`      // 首次重试等待时间
+	MaxBackoff         time.Duration `mapstructure:"max_backoff"`          // 最大等待时间
+	Multiplier         float64       `mapstructure:"multiplier"`           // 退避倍数, 默认 2
+	Jitter             float64       `mapstructure:"jitter"`               // 抖动比例 0~1, 等待时间在 [wait*(1-jitter), wait] 间随机
+	RetryOnStatus      []int         `mapstructure:"retry_on_status"`      // 需要重试的状态码, 为空时使用 DefaultRetryOnStatus
+	RetryNonIdempotent bool          `mapstructure:"retry_non_idempotent"` // 是否重试非幂等请求(POST/PATCH)
+}
+
+// DefaultRetryOnStatus 默认需要重试的状态码
+var DefaultRetryOnStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryConfig 默认重试策略
+var DefaultRetryConfig = &RetryConfig{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// IdempotencyKeyHeader 携带该请求头的非幂等请求视为可安全重试
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IsIdempotent 判断请求是否幂等
+// @param request *http.Request 请求
+func IsIdempotent(request *http.Request) bool {
+	switch request.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return request.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+func (r *RetryConfig) canRetry(request *http.Request) bool {
+	return r.RetryNonIdempotent || IsIdempotent(request)
+}
+
+func (r *RetryConfig) shouldRetry(response *http.Response, err error) bool {
+	if err != nil {
+		// 调用方取消或超时、熔断、限流不再重试
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) &&
+			!errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrRateLimited)
+	}
+	statuses := r.RetryOnStatus
+	if len(statuses) == 0 {
+		statuses = DefaultRetryOnStatus
+	}
+	for _, status := range statuses {
+		if response.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff 计算第 attempt 次失败后的等待时间, 优先使用响应的 Retry-After
+func (r *RetryConfig) backoff(attempt int, response *http.Response) time.Duration {
+	if wait, ok := retryAfter(response); ok {
+		if r.MaxBackoff > 0 && wait > r.MaxBackoff {
+			wait = r.MaxBackoff
+		}
+		return wait
+	}
+
+	multiplier := r.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	wait := float64(r.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if r.MaxBackoff > 0 && wait > float64(r.MaxBackoff) {
+		wait = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		jitter := math.Min(r.Jitter, 1)
+		wait -= wait * jitter * rand.Float64()
+	}
+	return time.Duration(wait)
+}
+
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
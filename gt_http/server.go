@@ -0,0 +1,143 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/INT-Game/go-tools/gt_sys"
+	"github.com/INT-Game/go-tools/slog"
+	"github.com/INT-Game/go-tools/slog/loggers/gin_logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ServerConfig HTTP服务配置
+type ServerConfig struct {
+	Addr              string        `mapstructure:"addr"`                // 监听地址, 如 :8080
+	Mode              string        `mapstructure:"mode"`                // gin 运行模式 debug / release / test, 为空时不修改
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`        // 读取请求超时
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"` // 读取请求头超时
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`       // 写响应超时
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`        // keep-alive 空闲超时
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`    // 停止时等待处理中请求完成的最长时间, 默认 10s
+	KeepLogOpen       bool          `mapstructure:"keep_log_open"`       // 停止后不关闭 slog
+}
+
+// Server 集成 gin、slog 请求日志和 gt_sys.StopWatcher 的HTTP服务
+type Server struct {
+	Engine *gin.Engine
+
+	config     ServerConfig
+	httpServer *http.Server
+	mu         sync.Mutex
+	addr       net.Addr
+}
+
+// NewServer 创建HTTP服务, gin 引擎已注册追踪上下文、GinZapHandler 和 GinZapRecoveryHandler
+// 应在 slog.Init 之后调用
+// @param config ServerConfig 服务配置
+func NewServer(config ServerConfig) *Server {
+	if config.ShutdownTimeout <= 0 {
+		config.ShutdownTimeout = 10 * time.Second
+	}
+	if config.Mode != "" {
+		gin.SetMode(config.Mode)
+	}
+	if slog.ZapLogger != nil {
+		gin_logger.SetupGinZapLogger(slog.ZapLogger)
+	}
+
+	engine := gin.New()
+	engine.Use(GinTraceHandler)
+	gin_logger.SetupGinEngineZapLogger(engine, slog.ZapLogger)
+
+	return &Server{
+		Engine: engine,
+		config: config,
+		httpServer: &http.Server{
+			Addr:              config.Addr,
+			Handler:           engine,
+			ReadTimeout:       config.ReadTimeout,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+		},
+	}
+}
+
+// GinTraceHandler 从 x-req-id / x-tra-id 请求头生成追踪上下文, 同时写入 gin 上下文和 c.Request 的 context
+// 处理函数使用 c.Request.Context() 调用 gt_http 时可将追踪信息传递给下游
+var GinTraceHandler gin.HandlerFunc = func(c *gin.Context) {
+	ctx := gin_logger.GetGinTraceCtx(c.Request.Context(), c)
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+// Addr 获取实际监听的地址, 未开始监听时返回 nil
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Run 开始监听并阻塞, 直到 ctx 结束或收到 gt_sys.StopWatcher 监听的退出信号
+// 停止时等待处理中的请求完成(最长 ShutdownTimeout), 然后关闭 slog
+// @param ctx context.Context 上下文
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.addr = listener.Addr()
+	s.mu.Unlock()
+	httpLogger.CInfo(ctx, "HTTP服务开始监听: %s", listener.Addr())
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stopped := make(chan struct{})
+	go gt_sys.StopWatcher(watchCtx, func() {
+		close(stopped)
+	})
+
+	select {
+	case err = <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		s.closeLog()
+		return err
+	case <-stopped:
+	}
+	return s.shutdown(ctx)
+}
+
+func (s *Server) shutdown(ctx context.Context) error {
+	httpLogger.CInfo(ctx, "HTTP服务停止中, 等待处理中的请求完成")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.ShutdownTimeout)
+	defer cancel()
+
+	err := s.httpServer.Shutdown(shutdownCtx)
+	if err != nil {
+		httpLogger.CError(ctx, "HTTP服务停止超时, 强制关闭: %v", err)
+		_ = s.httpServer.Close()
+	} else {
+		httpLogger.CInfo(ctx, "HTTP服务已停止")
+	}
+	s.closeLog()
+	return err
+}
+
+func (s *Server) closeLog() {
+	if !s.config.KeepLogOpen {
+		slog.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package gt_http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/log_context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	observeLogs(t)
+	server := NewServer(ServerConfig{Addr: "127.0.0.1:0", Mode: gin.TestMode, ShutdownTimeout: time.Second, KeepLogOpen: true})
+	started := make(chan struct{})
+	server.Engine.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		traId, _ := log_context.GetLogContextValueAsString(c.Request.Context(), log_context.CtxTraceId)
+		c.String(http.StatusOK, traId)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(ctx)
+	}()
+	assert.Eventually(t, func() bool { return server.Addr() != nil }, time.Second, 5*time.Millisecond)
+
+	type result struct {
+		body []byte
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		body, err := HttpGet("http://"+server.Addr().String()+"/slow", map[string]string{log_context.GinCtxTraceIdKeyStr: "tra-1"})
+		results <- result{body, err}
+	}()
+
+	<-started
+	cancel()
+
+	res := <-results
+	assert.NoError(t, res.err)
+	assert.Equal(t, "tra-1", string(res.body))
+	assert.NoError(t, <-runErr)
+}
+
+func TestServer_ListenError(t *testing.T) {
+	server := NewServer(ServerConfig{Addr: "invalid-addr", Mode: gin.TestMode, KeepLogOpen: true})
+	assert.Error(t, server.Run(context.Background()))
+}
@@ -0,0 +1,276 @@
+package gt_http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/INT-Game/go-tools/gt_sign"
+	"github.com/INT-Game/go-tools/slog/log_context"
+)
+
+// 签名算法
+const (
+	SignMd5        = "md5"
+	SignHmacSha256 = "hmac-sha256"
+)
+
+// SignerConfig 请求签名配置
+// 待签名字符串为除签名字段外所有非空参数按key排序后的 k1=v1&k2=v2,
+// md5 算法在末尾拼接密钥后计算MD5, hmac-sha256 算法以密钥计算HMAC
+type SignerConfig struct {
+	Algorithm       string `mapstructure:"algorithm"`        // 签名算法 md5 / hmac-sha256, 默认 md5
+	Secret          string `mapstructure:"secret"`           // 密钥
+	SecretParam     string `mapstructure:"secret_param"`     // md5 算法拼接密钥的参数名, 如 key 表示拼接 &key=secret, 为空时直接拼接密钥
+	TimestampParam  string `mapstructure:"timestamp_param"`  // 时间戳参数名, 为空时不添加时间戳
+	TimestampMillis bool   `mapstructure:"timestamp_millis"` // 时间戳是否使用毫秒
+	NonceParam      string `mapstructure:"nonce_param"`      // 随机串参数名, 为空时不添加随机串
+	SignParam       string `mapstructure:"sign_param"`       // 签名参数名, 默认 sign
+	SignHeader      string `mapstructure:"sign_header"`      // 签名请求头, 非空时签名写入该请求头而不是参数
+	Uppercase       bool   `mapstructure:"uppercase"`        // 签名是否转为大写
+}
+
+// DefaultSignerConfig 默认签名配置
+var DefaultSignerConfig = SignerConfig{
+	Algorithm:      SignMd5,
+	SecretParam:    "key",
+	TimestampParam: "timestamp",
+	NonceParam:     "nonce",
+	SignParam:      "sign",
+}
+
+// Signer 请求签名器
+type Signer struct {
+	config SignerConfig
+}
+
+// NewSigner 创建请求签名器
+// @param config SignerConfig 签名配置
+func NewSigner(config SignerConfig) *Signer {
+	if config.Algorithm == "" {
+		config.Algorithm = SignMd5
+	}
+	if config.SignParam == "" {
+		config.SignParam = "sign"
+	}
+	return &Signer{config: config}
+}
+
+// Sign 计算参数的签名, 签名字段本身不参与计算
+// @param params map[string]string 参数
+func (s *Signer) Sign(params map[string]string) string {
+	str := gt_sign.GetSortedParamString(params, s.config.SignParam)
+	var sign string
+	switch s.config.Algorithm {
+	case SignHmacSha256:
+		sign = gt_sign.GetHmacSha256String([]byte(s.config.Secret), []byte(str))
+	default:
+		if s.config.SecretParam != "" {
+			if str != "" {
+				str += "&"
+			}
+			str += s.config.SecretParam + "=" + s.config.Secret
+		} else {
+			str += s.config.Secret
+		}
+		sign = gt_sign.GetMd5String([]byte(str))
+	}
+	if s.config.Uppercase {
+		sign = strings.ToUpper(sign)
+	}
+	return sign
+}
+
+// Middleware 签名中间件, 每次发送(含重试)都会生成新的时间戳和随机串
+// 查询参数、表单请求体和JSON对象请求体中的参数参与签名, 同名参数以查询参数为准, JSON的非字符串值按 gt_sign.GetJsonParams 转换,
+// 时间戳、随机串和签名写入请求体(表单或JSON)或查询参数
+func (s *Signer) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			signed, err := s.signRequest(request)
+			if err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(signed)
+		})
+	}
+}
+
+func (s *Signer) signRequest(request *http.Request) (*http.Request, error) {
+	signed := request.Clone(request.Context())
+	body, err := readFullRequestBody(request)
+	// 签名后的请求使用缓存的请求体副本, 原请求体不再使用
+	closeRequestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	query := signed.URL.Query()
+	bodyType := getBodyType(signed.Header.Get("Content-Type"), body)
+	params, err := collectParams(query, bodyType, body)
+	if err != nil {
+		return nil, err
+	}
+
+	extra := map[string]string{}
+	if s.config.TimestampParam != "" {
+		now := time.Now()
+		timestamp := now.Unix()
+		if s.config.TimestampMillis {
+			timestamp = now.UnixMilli()
+		}
+		extra[s.config.TimestampParam] = strconv.FormatInt(timestamp, 10)
+	}
+	if s.config.NonceParam != "" {
+		extra[s.config.NonceParam] = log_context.NewId()
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
+	sign := s.Sign(params)
+	if s.config.SignHeader != "" {
+		signed.Header.Set(s.config.SignHeader, sign)
+	} else {
+		extra[s.config.SignParam] = sign
+	}
+
+	if bodyType != bodyTypeNone {
+		// 查询参数优先于请求体, 移除同名的查询参数以免验签方取到旧值
+		removed := false
+		for k := range extra {
+			if query.Has(k) {
+				query.Del(k)
+				removed = true
+			}
+		}
+		if removed {
+			signed.URL.RawQuery = query.Encode()
+		}
+	}
+	switch bodyType {
+	case bodyTypeForm:
+		form, _ := url.ParseQuery(string(body))
+		for k, v := range extra {
+			form.Set(k, v)
+		}
+		body = []byte(form.Encode())
+	case bodyTypeJSON:
+		object := map[string]json.RawMessage{}
+		_ = json.Unmarshal(body, &object)
+		for k, v := range extra {
+			object[k], _ = json.Marshal(v)
+		}
+		if body, err = json.Marshal(object); err != nil {
+			return nil, err
+		}
+	default:
+		for k, v := range extra {
+			query.Set(k, v)
+		}
+		signed.URL.RawQuery = query.Encode()
+	}
+
+	if body != nil {
+		setRequestBody(signed, body)
+	}
+	return signed, nil
+}
+
+type bodyType int
+
+const (
+	bodyTypeNone bodyType = iota
+	bodyTypeForm
+	bodyTypeJSON
+)
+
+func getBodyType(contentType string, body []byte) bodyType {
+	if body == nil {
+		return bodyTypeNone
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case ContentTypeForm:
+		return bodyTypeForm
+	case ContentTypeJSON:
+		if len(body) == 0 || bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+			return bodyTypeJSON
+		}
+	}
+	return bodyTypeNone
+}
+
+// collectParams 收集查询参数和请求体中的参数, 同名参数取第一个值, 查询参数优先于请求体
+// JSON 请求体仅支持对象, 非字符串的值按 gt_sign.GetJsonParams 的规则转换
+func collectParams(query url.Values, bodyType bodyType, body []byte) (map[string]string, error) {
+	params := map[string]string{}
+	for k, v := range query {
+		if len(v) > 0 {
+			params[k] = v[0]
+		}
+	}
+
+	var bodyParams map[string]string
+	switch bodyType {
+	case bodyTypeForm:
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		bodyParams = make(map[string]string, len(form))
+		for k, v := range form {
+			if len(v) > 0 {
+				bodyParams[k] = v[0]
+			}
+		}
+	case bodyTypeJSON:
+		var err error
+		if bodyParams, err = gt_sign.GetJsonParams(body); err != nil {
+			return nil, fmt.Errorf("签名请求体解析失败: %w", err)
+		}
+	}
+	for k, v := range bodyParams {
+		if _, ok := params[k]; !ok {
+			params[k] = v
+		}
+	}
+	return params, nil
+}
+
+// readFullRequestBody 读取请求体副本, 无请求体时返回 nil
+func readFullRequestBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil || request.Body == http.NoBody {
+		return nil, nil
+	}
+	if request.GetBody == nil {
+		return nil, errors.New("请求体不可重放, 无法签名")
+	}
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, err := io.ReadAll(body)
+	if data == nil {
+		data = []byte{}
+	}
+	return data, err
+}
+
+func setRequestBody(request *http.Request, body []byte) {
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
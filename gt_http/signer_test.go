@@ -0,0 +1,134 @@
+package gt_http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/INT-Game/go-tools/gt_sign"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_Sign(t *testing.T) {
+	params := map[string]string{"b": "2", "a": "1", "empty": "", "sign": "old"}
+
+	md5Signer := NewSigner(SignerConfig{Secret: "s", SecretParam: "key"})
+	assert.Equal(t, gt_sign.GetMd5String([]byte("a=1&b=2&key=s")), md5Signer.Sign(params))
+
+	rawSigner := NewSigner(SignerConfig{Secret: "s", Uppercase: true})
+	assert.Equal(t, strings.ToUpper(gt_sign.GetMd5String([]byte("a=1&b=2s"))), rawSigner.Sign(params))
+
+	hmacSigner := NewSigner(SignerConfig{Algorithm: SignHmacSha256, Secret: "s"})
+	assert.Equal(t, gt_sign.GetHmacSha256String([]byte("s"), []byte("a=1&b=2")), hmacSigner.Sign(params))
+}
+
+func TestGetJsonParams(t *testing.T) {
+	params, err := gt_sign.GetJsonParams([]byte(`{"a":1.50,"b":"x","c":{"z":[1, 2],"y":null},"d":true,"e":null,"f":"<&>"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1.50", "b": "x", "c": `{"y":null,"z":[1,2]}`, "d": "true", "f": "<&>"}, params)
+	assert.Equal(t, `a=1.50&b=x&c={"y":null,"z":[1,2]}&d=true&f=<&>`, gt_sign.GetSortedParamString(params))
+
+	_, err = gt_sign.GetJsonParams([]byte(`[1]`))
+	assert.Error(t, err)
+}
+
+func TestSigner_Middleware(t *testing.T) {
+	config := DefaultSignerConfig
+	config.Secret = "secret"
+	signer := NewSigner(config)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		params, err := collectParams(r.URL.Query(), getBodyType(r.Header.Get("Content-Type"), body), body)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, params["timestamp"])
+		assert.NotEmpty(t, params["nonce"])
+		sign := params["sign"]
+		if sign == "" {
+			sign = r.Header.Get("X-Sign")
+		}
+		assert.Equal(t, signer.Sign(params), sign)
+		_ = json.NewEncoder(w).Encode(params)
+	}))
+	defer server.Close()
+
+	client := NewClient(DefaultClientConfig).Use(signer.Middleware())
+	ctx := context.Background()
+
+	t.Run("query", func(t *testing.T) {
+		params, err := DoJSON[map[string]string](ctx, client, http.MethodGet, server.URL+"?a=1", nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", params["a"])
+	})
+
+	t.Run("form", func(t *testing.T) {
+		body, err := client.NewRequest(http.MethodPost, server.URL+"?q=x").SetFormBody(map[string]string{"a": "1"}).Do(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, string(body), `"q":"x"`)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		params, err := DoJSON[map[string]string](ctx, client, http.MethodPost, server.URL, nil, map[string]any{"a": 1, "b": "x", "c": []int{1}})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", params["a"])
+		assert.Equal(t, "[1]", params["c"])
+	})
+
+	t.Run("query takes precedence", func(t *testing.T) {
+		params, err := DoJSON[map[string]string](ctx, client, http.MethodPost, server.URL+"?a=q&nonce=old", nil, map[string]any{"a": "body", "b": 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "q", params["a"])
+		assert.Equal(t, "2", params["b"])
+		assert.NotEqual(t, "old", params["nonce"])
+	})
+
+	t.Run("header", func(t *testing.T) {
+		headerConfig := config
+		headerConfig.SignHeader = "X-Sign"
+		headerClient := NewClient(DefaultClientConfig).Use(NewSigner(headerConfig).Middleware())
+		params, err := DoJSON[map[string]string](ctx, headerClient, http.MethodPost, server.URL, nil, map[string]any{"a": 1})
+		assert.NoError(t, err)
+		assert.Empty(t, params["sign"])
+	})
+}
+
+// closeTracker 记录请求体是否已关闭
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSigner_ClosesBody(t *testing.T) {
+	config := DefaultSignerConfig
+	config.Secret = "secret"
+	next := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		_ = request.Body.Close()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: request}, nil
+	})
+	roundTripper := NewSigner(config).Middleware()(next)
+
+	request, err := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(`{"a":1}`))
+	assert.NoError(t, err)
+	request.Header.Set("Content-Type", ContentTypeJSON)
+	body := &closeTracker{Reader: strings.NewReader(`{"a":1}`)}
+	request.Body = body
+	_, err = roundTripper.RoundTrip(request)
+	assert.NoError(t, err)
+	assert.True(t, body.closed)
+
+	// 请求体不可重放时返回错误, 同样关闭请求体
+	body = &closeTracker{Reader: strings.NewReader(`{"a":1}`)}
+	request.Body, request.GetBody = body, nil
+	_, err = roundTripper.RoundTrip(request)
+	assert.Error(t, err)
+	assert.True(t, body.closed)
+}
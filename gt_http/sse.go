@@ -0,0 +1,284 @@
+package gt_http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEEvent Server-Sent Events 事件
+type SSEEvent struct {
+	ID    string // 事件 id, 未设置时为上一个事件的 id
+	Event string // 事件类型, 默认 message
+	Data  string // 事件数据, 多行 data 以 \n 连接
+}
+
+// SSEConfig SSE 订阅配置
+type SSEConfig struct {
+	Header           map[string]string `mapstructure:"header"`            // 请求头
+	LastEventID      string            `mapstructure:"last_event_id"`     // 首次连接发送的 Last-Event-ID
+	RetryDelay       time.Duration     `mapstructure:"retry_delay"`       // 重连间隔, 默认 3s, 服务端 retry 字段会覆盖
+	MaxRetries       int               `mapstructure:"max_retries"`       // 连续重连失败的最大次数, 0 表示不限制
+	DisableReconnect bool              `mapstructure:"disable_reconnect"` // 连接断开后不重连
+	BufferSize       int               `mapstructure:"buffer_size"`       // 事件通道缓冲, 默认 16
+	MaxLineSize      int               `mapstructure:"max_line_size"`     // 单行最大字节数, 默认 1MB
+}
+
+// SSEStream SSE 订阅, 事件从 Events 读取, Events 关闭后可通过 Err 获取结束原因
+type SSEStream struct {
+	Events <-chan SSEEvent
+
+	mu          sync.Mutex
+	err         error
+	lastEventID string
+}
+
+// Err 订阅结束的原因, ctx 取消时为 ctx 的错误, 服务端返回 204 时为 nil
+func (s *SSEStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// LastEventID 最后收到的事件 id, 可用于下次订阅时续传
+func (s *SSEStream) LastEventID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastEventID
+}
+
+func (s *SSEStream) setLastEventID(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastEventID = id
+}
+
+// SubscribeSSE 使用默认客户端订阅 SSE
+// @param ctx context.Context 上下文, 取消时结束订阅
+// @param url string 请求地址
+// @param config SSEConfig 订阅配置
+func SubscribeSSE(ctx context.Context, url string, config SSEConfig) *SSEStream {
+	return DefaultClient.SubscribeSSE(ctx, url, config)
+}
+
+// SubscribeSSE 订阅 SSE, 连接断开后携带 Last-Event-ID 自动重连
+// 不应用客户端的 Timeout, 订阅时长由 ctx 控制; 服务端返回 204 或除 429 外的 4xx 时不再重连
+// @param ctx context.Context 上下文, 取消时结束订阅
+// @param url string 请求地址
+// @param config SSEConfig 订阅配置
+func (c *Client) SubscribeSSE(ctx context.Context, url string, config SSEConfig) *SSEStream {
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 3 * time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 16
+	}
+	if config.MaxLineSize <= 0 {
+		config.MaxLineSize = 1 << 20
+	}
+
+	events := make(chan SSEEvent, config.BufferSize)
+	stream := &SSEStream{Events: events, lastEventID: config.LastEventID}
+	go func() {
+		defer close(events)
+		err := stream.run(ctx, c, url, config, events)
+		stream.mu.Lock()
+		stream.err = err
+		stream.mu.Unlock()
+	}()
+	return stream
+}
+
+func (s *SSEStream) run(ctx context.Context, c *Client, url string, config SSEConfig, events chan<- SSEEvent) error {
+	retryDelay := config.RetryDelay
+	failures := 0
+	for {
+		connected, err := s.connect(ctx, c, url, config, events, &retryDelay)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil && !connected {
+			// 204 表示服务端要求停止重连
+			return nil
+		}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 &&
+			statusErr.StatusCode != http.StatusTooManyRequests {
+			return err
+		}
+		if config.DisableReconnect {
+			return err
+		}
+		if connected {
+			failures = 0
+		} else {
+			failures++
+			if config.MaxRetries > 0 && failures > config.MaxRetries {
+				return err
+			}
+		}
+
+		httpLogger.CWarnw(ctx, "SSE 连接断开, 准备重连", "url", url, "delay", retryDelay, "last_event_id", s.LastEventID(), "error", err)
+		if err = sleepContext(ctx, retryDelay); err != nil {
+			return err
+		}
+	}
+}
+
+// connect 建立一次连接并读取事件, connected 表示是否成功建立连接
+func (s *SSEStream) connect(ctx context.Context, c *Client, url string, config SSEConfig, events chan<- SSEEvent, retryDelay *time.Duration) (connected bool, err error) {
+	request := c.NewRequest(http.MethodGet, url).
+		SetHeaders(config.Header).
+		SetHeader("Accept", "text/event-stream").
+		SetHeader("Cache-Control", "no-cache")
+	if lastEventID := s.LastEventID(); lastEventID != "" {
+		request.SetHeader("Last-Event-ID", lastEventID)
+	}
+
+	response, err := request.Send(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	if err = c.checkStatus(response); err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 0, 4096), config.MaxLineSize)
+	scanner.Split(scanSSELines)
+
+	var event SSEEvent
+	var data strings.Builder
+	hasData := false
+	// id 先写入缓冲, 事件分发时才提交为 LastEventID, 连接在事件中途断开时重连不会跳过该事件
+	idBuffer := s.LastEventID()
+	first := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if first {
+			line = strings.TrimPrefix(line, "\ufeff")
+			first = false
+		}
+
+		if line == "" {
+			if hasData {
+				event.Data = data.String()
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				event.ID = idBuffer
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return true, ctx.Err()
+				}
+			}
+			s.setLastEventID(idBuffer)
+			event, hasData = SSEEvent{}, false
+			data.Reset()
+			continue
+		}
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				idBuffer = value
+			}
+		case "retry":
+			if ms, parseErr := strconv.ParseUint(value, 10, 63); parseErr == nil {
+				*retryDelay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err = scanner.Err(); err == nil {
+		err = errors.New("SSE 连接已关闭")
+	}
+	return true, err
+}
+
+// scanSSELines 按 \r\n、\n 或 \r 分行
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 < len(data) {
+				if data[i+1] == '\n' {
+					return i + 2, data[:i], nil
+				}
+				return i + 1, data[:i], nil
+			}
+			if !atEOF {
+				// 需要更多数据判断是否为 \r\n
+				return 0, nil, nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// StreamLines 发送请求并逐行读取响应体, 适用于 NDJSON 等分块传输的流式响应
+// 不应用客户端的 Timeout, 读取时长由 ctx 控制; fn 返回错误时停止读取并返回该错误
+// @param ctx context.Context 上下文
+// @param fn func(line []byte) error 行处理函数, line 不含换行符, 仅在回调内有效
+func (r *Request) StreamLines(ctx context.Context, fn func(line []byte) error) error {
+	body, err := r.Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	reader := bufio.NewReader(body)
+	for {
+		line, readErr := reader.ReadSlice('\n')
+		if errors.Is(readErr, bufio.ErrBufferFull) {
+			// 超长的行拼接完整后再回调
+			full := append([]byte(nil), line...)
+			for errors.Is(readErr, bufio.ErrBufferFull) {
+				line, readErr = reader.ReadSlice('\n')
+				full = append(full, line...)
+			}
+			line = full
+		}
+		if len(line) > 0 {
+			if err = fn(bytes.TrimRight(line, "\r\n")); err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			if readErr == io.EOF {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
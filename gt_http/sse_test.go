@@ -0,0 +1,187 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeSSE(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		switch attempt {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "\ufeff: comment\nretry: 10\n\n")
+			_, _ = fmt.Fprint(w, "id: 1\ndata: hello\n\n")
+			_, _ = fmt.Fprint(w, "event: update\r\ndata: line1\r\ndata:line2\r\nid: 2\r\n\r\n")
+			// 连接断开时未结束的事件被丢弃
+			_, _ = fmt.Fprint(w, "data: partial\n")
+		case 2:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "data: resumed\r\r")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	stream := NewClient(DefaultClientConfig).SubscribeSSE(context.Background(), server.URL, SSEConfig{RetryDelay: time.Minute})
+	var events []SSEEvent
+	for event := range stream.Events {
+		events = append(events, event)
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, []SSEEvent{
+		{ID: "1", Event: "message", Data: "hello"},
+		{ID: "2", Event: "update", Data: "line1\nline2"},
+		{ID: "2", Event: "message", Data: "resumed"},
+	}, events)
+	assert.Equal(t, "2", stream.LastEventID())
+	assert.Equal(t, []string{"", "2", "2"}, lastEventIDs)
+}
+
+func TestSubscribeSSE_CutMidEvent(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+
+		switch attempt {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "retry: 1\nid: 1\ndata: first\n\n")
+			// 事件 2 的 id 已发送, 空行之前连接断开
+			_, _ = fmt.Fprint(w, "id: 2\ndata: cut")
+		case 2:
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprint(w, "id: 2\ndata: second\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	stream := SubscribeSSE(context.Background(), server.URL, SSEConfig{})
+	var events []SSEEvent
+	for event := range stream.Events {
+		events = append(events, event)
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, []SSEEvent{
+		{ID: "1", Event: "message", Data: "first"},
+		{ID: "2", Event: "message", Data: "second"},
+	}, events)
+	// 重连时只提交已分发事件的 id
+	assert.Equal(t, []string{"", "1", "2"}, lastEventIDs)
+}
+
+func TestSubscribeSSE_Stop(t *testing.T) {
+	t.Run("client error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		stream := SubscribeSSE(context.Background(), server.URL, SSEConfig{RetryDelay: time.Millisecond})
+		for range stream.Events {
+		}
+		assert.Equal(t, http.StatusUnauthorized, GetStatusCode(stream.Err()))
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		var mu sync.Mutex
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			attempts++
+			mu.Unlock()
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		stream := SubscribeSSE(context.Background(), server.URL, SSEConfig{RetryDelay: time.Millisecond, MaxRetries: 2})
+		for range stream.Events {
+		}
+		assert.Equal(t, http.StatusInternalServerError, GetStatusCode(stream.Err()))
+		mu.Lock()
+		assert.Equal(t, 3, attempts)
+		mu.Unlock()
+	})
+
+	t.Run("context cancel", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprint(w, "data: first\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := SubscribeSSE(ctx, server.URL, SSEConfig{})
+		event := <-stream.Events
+		assert.Equal(t, "first", event.Data)
+		cancel()
+		for range stream.Events {
+		}
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+}
+
+func TestScanSSELines(t *testing.T) {
+	advance, token, _ := scanSSELines([]byte("a\r"), false)
+	assert.Equal(t, 0, advance)
+	assert.Nil(t, token)
+
+	advance, token, _ = scanSSELines([]byte("a\r"), true)
+	assert.Equal(t, 2, advance)
+	assert.Equal(t, "a", string(token))
+
+	advance, token, _ = scanSSELines([]byte("a\rb"), false)
+	assert.Equal(t, 2, advance)
+	assert.Equal(t, "a", string(token))
+}
+
+func TestRequest_StreamLines(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "{\"n\":1}\r\n")
+		w.(http.Flusher).Flush()
+		_, _ = fmt.Fprint(w, long+"\n{\"n\":3}")
+	}))
+	defer server.Close()
+
+	var lines []string
+	err := NewRequest(http.MethodGet, server.URL).StreamLines(context.Background(), func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"n":1}`, long, `{"n":3}`}, lines)
+
+	stop := errors.New("stop")
+	err = NewRequest(http.MethodGet, server.URL).StreamLines(context.Background(), func(line []byte) error {
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+}
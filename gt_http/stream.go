@@ -0,0 +1,195 @@
+package gt_http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/INT-Game/go-tools/gt_sign"
+)
+
+// ErrChecksumMismatch 下载文件校验失败
+var ErrChecksumMismatch = errors.New("文件校验失败")
+
+// Stream 发送请求并返回响应体, 状态码不被接受时返回 *StatusError
+// 不应用客户端的 Timeout, 读取时长由 ctx 控制; 调用方负责关闭返回的响应体
+// @param ctx context.Context 上下文
+func (r *Request) Stream(ctx context.Context) (io.ReadCloser, error) {
+	response, err := r.Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.client.checkStatus(response); err != nil {
+		_ = response.Body.Close()
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// HttpGetStream 发送GET请求并返回响应体, 适用于读取大文件等不宜一次读入内存的响应
+// 调用方负责关闭返回的响应体
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+func HttpGetStream(ctx context.Context, url string, header map[string]string) (io.ReadCloser, error) {
+	return NewRequest(http.MethodGet, url).SetHeaders(header).Stream(ctx)
+}
+
+// ProgressFunc 下载进度回调
+// @param written int64 已写入的字节数(含续传前已有的部分)
+// @param total int64 文件总字节数, 未知时为 -1
+type ProgressFunc func(written int64, total int64)
+
+// DownloadConfig 下载配置
+type DownloadConfig struct {
+	Resume            bool         // 是否断点续传, 未完成的内容保存在 path + DownloadTempSuffix
+	Progress          ProgressFunc // 进度回调
+	Checksum          string       // 期望的文件校验值(十六进制, 不区分大小写), 为空时不校验
+	ChecksumAlgorithm string       // 校验算法 md5 / sha256, 默认 md5
+}
+
+// DownloadTempSuffix 下载未完成时临时文件的后缀
+const DownloadTempSuffix = ".download"
+
+// Download 使用默认客户端下载文件
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param path string 保存路径
+// @param config DownloadConfig 下载配置
+func Download(ctx context.Context, url string, header map[string]string, path string, config DownloadConfig) (int64, error) {
+	return DefaultClient.Download(ctx, url, header, path, config)
+}
+
+// Download 下载文件到 path, 先写入临时文件, 完成且校验通过后重命名
+// 开启断点续传时通过 Range 请求头从临时文件末尾继续下载
+// @param ctx context.Context 上下文
+// @param url string 请求地址
+// @param header map[string]string 请求头
+// @param path string 保存路径
+// @param config DownloadConfig 下载配置
+// @return size int64 文件大小
+func (c *Client) Download(ctx context.Context, url string, header map[string]string, path string, config DownloadConfig) (size int64, err error) {
+	tempPath := path + DownloadTempSuffix
+	var offset int64
+	if config.Resume {
+		if info, statErr := os.Stat(tempPath); statErr == nil {
+			offset = info.Size()
+		}
+	}
+
+	request := c.NewRequest(http.MethodGet, url).SetHeaders(header)
+	if offset > 0 {
+		request.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	response, err := request.Send(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	total := int64(-1)
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch {
+	case offset > 0 && response.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// 临时文件已是完整内容
+		total = offset
+	case offset > 0 && response.StatusCode == http.StatusPartialContent:
+		flag = os.O_WRONLY | os.O_APPEND
+		total = parseContentRangeTotal(response.Header.Get("Content-Range"))
+	default:
+		if err = c.checkStatus(response); err != nil {
+			return 0, err
+		}
+		// 服务端不支持 Range 时从头下载
+		offset = 0
+		if response.ContentLength >= 0 {
+			total = response.ContentLength
+		}
+	}
+
+	if response.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		file, openErr := os.OpenFile(tempPath, flag, 0644)
+		if openErr != nil {
+			return 0, openErr
+		}
+		writer := &progressWriter{writer: file, written: offset, total: total, progress: config.Progress}
+		_, err = io.Copy(writer, response.Body)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return writer.written, err
+		}
+	}
+
+	if err = verifyChecksum(tempPath, config); err != nil {
+		_ = os.Remove(tempPath)
+		return 0, err
+	}
+	if err = os.Rename(tempPath, path); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func verifyChecksum(path string, config DownloadConfig) error {
+	if config.Checksum == "" {
+		return nil
+	}
+	var checksum string
+	var err error
+	switch strings.ToLower(config.ChecksumAlgorithm) {
+	case "sha256":
+		checksum, err = gt_sign.GetFileSha256String(path)
+	default:
+		checksum, err = gt_sign.GetFileMd5String(path)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(checksum, config.Checksum) {
+		return fmt.Errorf("%w: 期望 %s, 实际 %s", ErrChecksumMismatch, config.Checksum, checksum)
+	}
+	return nil
+}
+
+// parseContentRangeTotal 解析 Content-Range: bytes 100-199/200 中的总大小, 未知时返回 -1
+func parseContentRangeTotal(contentRange string) int64 {
+	i := strings.LastIndexByte(contentRange, '/')
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
+
+type progressWriter struct {
+	writer   io.Writer
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	if w.progress != nil && n > 0 {
+		w.progress(w.written, w.total)
+	}
+	return n, err
+}
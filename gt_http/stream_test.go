@@ -0,0 +1,105 @@
+package gt_http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/INT-Game/go-tools/gt_sign"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpGetStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(strings.Repeat("a", 1000)))
+	}))
+	defer server.Close()
+
+	reader, err := HttpGetStream(context.Background(), server.URL, nil)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Len(t, data, 1000)
+	assert.NoError(t, reader.Close())
+
+	_, err = HttpGetStream(context.Background(), server.URL+"/missing", nil)
+	assert.Equal(t, http.StatusNotFound, GetStatusCode(err))
+}
+
+func TestDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	checksum := gt_sign.GetMd5String(content)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "data.bin", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	t.Run("download with progress and checksum", func(t *testing.T) {
+		path := filepath.Join(dir, "a.bin")
+		var lastWritten, lastTotal int64
+		size, err := Download(ctx, server.URL, nil, path, DownloadConfig{
+			Checksum: strings.ToUpper(checksum),
+			Progress: func(written int64, total int64) {
+				lastWritten, lastTotal = written, total
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+		assert.Equal(t, int64(len(content)), lastWritten)
+		assert.Equal(t, int64(len(content)), lastTotal)
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, content, data)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		ranges = nil
+		path := filepath.Join(dir, "b.bin")
+		assert.NoError(t, os.WriteFile(path+DownloadTempSuffix, content[:4000], 0644))
+		size, err := Download(ctx, server.URL, nil, path, DownloadConfig{
+			Resume:            true,
+			Checksum:          gt_sign.GetSha256String(content),
+			ChecksumAlgorithm: "sha256",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+		assert.Equal(t, []string{"bytes=4000-"}, ranges)
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, content, data)
+		_, err = os.Stat(path + DownloadTempSuffix)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("resume complete temp file", func(t *testing.T) {
+		path := filepath.Join(dir, "c.bin")
+		assert.NoError(t, os.WriteFile(path+DownloadTempSuffix, content, 0644))
+		size, err := Download(ctx, server.URL, nil, path, DownloadConfig{Resume: true, Checksum: checksum})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		path := filepath.Join(dir, "d.bin")
+		_, err := Download(ctx, server.URL, nil, path, DownloadConfig{Checksum: "00"})
+		assert.True(t, errors.Is(err, ErrChecksumMismatch))
+		_, err = os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(path + DownloadTempSuffix)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,29 @@
+package gt_http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/INT-Game/go-tools/slog/log_context"
+)
+
+// SetTraceHeaders 将 ctx 中 log_context 的 reqId / traId 写入 x-req-id / x-tra-id 请求头
+// 与 gin_logger.GetGinTraceCtx 读取的请求头一致, 下游服务可沿用同一条调用链; 已存在的请求头不会被覆盖
+// @param ctx context.Context 上下文
+// @param header http.Header 请求头
+func SetTraceHeaders(ctx context.Context, header http.Header) {
+	if ctx == nil || header == nil {
+		return
+	}
+	setHeaderFromLogContext(ctx, header, log_context.GinCtxRequestIdKeyStr, log_context.CtxRequestId)
+	setHeaderFromLogContext(ctx, header, log_context.GinCtxTraceIdKeyStr, log_context.CtxTraceId)
+}
+
+func setHeaderFromLogContext(ctx context.Context, header http.Header, headerKey string, ctxKey string) {
+	if header.Get(headerKey) != "" {
+		return
+	}
+	if value, ok := log_context.GetLogContextValueAsString(ctx, ctxKey); ok && value != "" {
+		header.Set(headerKey, value)
+	}
+}
@@ -0,0 +1,65 @@
+package gt_http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/log_context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpGetCtx_TraceHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get(log_context.GinCtxRequestIdKeyStr) + "," + r.Header.Get(log_context.GinCtxTraceIdKeyStr)))
+	}))
+	defer server.Close()
+
+	t.Run("propagate ids from log context", func(t *testing.T) {
+		ctx := log_context.SetTrackLogContext(context.Background(), "req-1", "tra-1")
+		body, err := HttpGetCtx(ctx, server.URL, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "req-1,tra-1", string(body))
+	})
+
+	t.Run("explicit header wins", func(t *testing.T) {
+		ctx := log_context.SetTrackLogContext(context.Background(), "req-1", "tra-1")
+		body, err := HttpGetCtx(ctx, server.URL, map[string]string{log_context.GinCtxTraceIdKeyStr: "tra-2"})
+		assert.NoError(t, err)
+		assert.Equal(t, "req-1,tra-2", string(body))
+	})
+
+	t.Run("no ids without log context", func(t *testing.T) {
+		body, err := HttpGetCtx(context.Background(), server.URL, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ",", string(body))
+	})
+
+	t.Run("disabled by config", func(t *testing.T) {
+		config := DefaultClientConfig
+		config.DisableTraceHeaders = true
+		ctx := log_context.SetTrackLogContext(context.Background(), "req-1", "tra-1")
+		body, err := NewClient(config).GetCtx(ctx, server.URL, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ",", string(body))
+	})
+}
+
+func TestHttpPostCtx_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := HttpPostCtx(ctx, server.URL, nil, map[string]int{"a": 1})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,186 @@
+package gt_http
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrCertificatePin 服务端证书与固定的公钥不匹配
+var ErrCertificatePin = errors.New("服务端证书公钥不匹配")
+
+// TLSConfig 客户端TLS配置
+type TLSConfig struct {
+	RootCAFiles        []string `mapstructure:"root_ca_files"`        // 自定义根证书(PEM)文件, 为空时使用系统根证书
+	CertFile           string   `mapstructure:"cert_file"`            // 客户端证书(PEM)文件
+	KeyFile            string   `mapstructure:"key_file"`             // 客户端私钥(PEM)文件
+	ServerName         string   `mapstructure:"server_name"`          // 校验的服务端域名, 为空时使用请求的 host
+	InsecureSkipVerify bool     `mapstructure:"insecure_skip_verify"` // 跳过证书链校验, 仅用于测试; 仍会校验 PinnedPublicKeys
+	PinnedPublicKeys   []string `mapstructure:"pinned_public_keys"`   // 固定的证书公钥 SHA256 (base64, 可带 sha256/ 前缀), 校验通过的证书链中任一证书匹配即通过; InsecureSkipVerify 时只比对服务端证书
+}
+
+// NewTransport 按客户端配置创建 *http.Transport
+// @param config ClientConfig 客户端配置
+func NewTransport(config ClientConfig) (*http.Transport, error) {
+	dialer := &net.Dialer{
+		Timeout:   config.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if config.DNSServer != "" {
+		dnsServer := config.DNSServer
+		if _, _, err := net.SplitHostPort(dnsServer); err != nil {
+			dnsServer = net.JoinHostPort(dnsServer, "53")
+		}
+		dnsDialer := &net.Dialer{Timeout: config.ConnectTimeout}
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+				return dnsDialer.DialContext(ctx, network, dnsServer)
+			},
+		}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("代理地址错误: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("不支持的代理协议: %s", proxyURL.Scheme)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           overrideDialContext(dialer.DialContext, config.HostOverrides),
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.ConnectTimeout,
+		ResponseHeaderTimeout: config.ReadTimeout,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+type dialContextFunc func(ctx context.Context, network string, addr string) (net.Conn, error)
+
+// overrideDialContext 按静态解析替换连接地址, TLS 仍使用原 host 校验证书
+func overrideDialContext(dial dialContextFunc, overrides map[string]string) dialContextFunc {
+	if len(overrides) == 0 {
+		return dial
+	}
+	return func(ctx context.Context, network string, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dial(ctx, network, addr)
+		}
+		target, ok := overrides[addr]
+		if !ok {
+			target, ok = overrides[host]
+		}
+		if ok {
+			if _, _, splitErr := net.SplitHostPort(target); splitErr != nil {
+				target = net.JoinHostPort(target, port)
+			}
+			addr = target
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+func newTLSConfig(config *TLSConfig) (*tls.Config, error) {
+	if config == nil {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if len(config.RootCAFiles) > 0 {
+		pool := x509.NewCertPool()
+		for _, file := range config.RootCAFiles {
+			pem, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("读取根证书失败: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("根证书格式错误: %s", file)
+			}
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(config.PinnedPublicKeys) > 0 {
+		pins := map[string]bool{}
+		for _, pin := range config.PinnedPublicKeys {
+			pin = strings.TrimPrefix(strings.TrimSpace(pin), "sha256/")
+			if _, err := base64.StdEncoding.DecodeString(pin); err != nil {
+				return nil, fmt.Errorf("固定公钥格式错误: %s", pin)
+			}
+			pins[pin] = true
+		}
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+			if config.InsecureSkipVerify {
+				// 未校验证书链时, 服务端附带的其他证书不能证明身份, 只有叶子证书经过了握手签名
+				if len(rawCerts) == 0 {
+					return ErrCertificatePin
+				}
+				cert, err := x509.ParseCertificate(rawCerts[0])
+				if err != nil {
+					return err
+				}
+				if pins[GetPublicKeyPin(cert)] {
+					return nil
+				}
+				return ErrCertificatePin
+			}
+			// 只比对校验通过的证书链, 服务端可以在证书链中附带任意公开的证书
+			for _, chain := range verifiedChains {
+				for _, cert := range chain {
+					if pins[GetPublicKeyPin(cert)] {
+						return nil
+					}
+				}
+			}
+			return ErrCertificatePin
+		}
+	}
+	return tlsConfig, nil
+}
+
+// GetPublicKeyPin 计算证书公钥的 SHA256 (base64), 用于 TLSConfig.PinnedPublicKeys
+// @param cert *x509.Certificate 证书
+func GetPublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,150 @@
+package gt_http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+	cert := server.Certificate()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0644))
+	port := mustParseURL(t, server.URL).Port()
+
+	t.Run("untrusted without root ca", func(t *testing.T) {
+		_, err := NewClient(DefaultClientConfig).Get(server.URL, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("root ca and host override", func(t *testing.T) {
+		config := DefaultClientConfig
+		// httptest 证书包含 example.com
+		config.TLS = &TLSConfig{RootCAFiles: []string{caFile}}
+		config.HostOverrides = map[string]string{"example.com": "127.0.0.1"}
+		body, err := NewClient(config).Get("https://example.com:"+port, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "example.com:"+port, string(body))
+	})
+
+	t.Run("pinned public key", func(t *testing.T) {
+		config := DefaultClientConfig
+		config.TLS = &TLSConfig{RootCAFiles: []string{caFile}, PinnedPublicKeys: []string{"sha256/" + GetPublicKeyPin(cert)}}
+		_, err := NewClient(config).Get(server.URL, nil)
+		assert.NoError(t, err)
+
+		config.TLS = &TLSConfig{InsecureSkipVerify: true, PinnedPublicKeys: []string{"AAAA"}}
+		_, err = NewClient(config).Get(server.URL, nil)
+		assert.True(t, errors.Is(err, ErrCertificatePin))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewTransport(ClientConfig{TLS: &TLSConfig{RootCAFiles: []string{"missing.pem"}}})
+		assert.Error(t, err)
+		_, err = NewTransport(ClientConfig{Proxy: "ftp://proxy"})
+		assert.Error(t, err)
+		assert.Panics(t, func() { NewClient(ClientConfig{TLS: &TLSConfig{CertFile: "missing.pem"}}) })
+		_, err = TryNewClient(ClientConfig{TLS: &TLSConfig{PinnedPublicKeys: []string{"not base64"}}})
+		assert.Error(t, err)
+	})
+}
+
+// newTestCert 生成证书, parent 为空时自签名
+func newTestCert(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestTransport_PinAppendedCert(t *testing.T) {
+	root, rootKey := newTestCert(t, "root", true, nil, nil)
+	leaf, leafKey := newTestCert(t, "leaf", false, root, rootKey)
+	// 公开的被固定证书, 攻击者没有私钥, 只能附带在证书链中
+	pinned, _ := newTestCert(t, "pinned", true, nil, nil)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leaf.Raw, pinned.Raw},
+		PrivateKey:  leafKey,
+	}}}
+	server.StartTLS()
+	defer server.Close()
+
+	rootFile := filepath.Join(t.TempDir(), "root.pem")
+	assert.NoError(t, os.WriteFile(rootFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: root.Raw}), 0644))
+	get := func(tlsConfig *TLSConfig) error {
+		config := DefaultClientConfig
+		config.TLS = tlsConfig
+		_, err := NewClient(config).Get(server.URL, nil)
+		return err
+	}
+
+	// 附带的证书不在校验通过的证书链中
+	err := get(&TLSConfig{RootCAFiles: []string{rootFile}, PinnedPublicKeys: []string{GetPublicKeyPin(pinned)}})
+	assert.ErrorIs(t, err, ErrCertificatePin)
+	// 跳过证书链校验时只比对叶子证书
+	err = get(&TLSConfig{InsecureSkipVerify: true, PinnedPublicKeys: []string{GetPublicKeyPin(pinned)}})
+	assert.ErrorIs(t, err, ErrCertificatePin)
+
+	assert.NoError(t, get(&TLSConfig{RootCAFiles: []string{rootFile}, PinnedPublicKeys: []string{GetPublicKeyPin(root)}}))
+	assert.NoError(t, get(&TLSConfig{InsecureSkipVerify: true, PinnedPublicKeys: []string{GetPublicKeyPin(leaf)}}))
+}
+
+func TestTransport_Proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 代理收到的是完整地址
+		_, _ = w.Write([]byte("proxy:" + r.URL.String()))
+	}))
+	defer proxy.Close()
+
+	config := DefaultClientConfig
+	config.Proxy = proxy.URL
+	body, err := NewClient(config).Get("http://upstream.test/path", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "proxy:http://upstream.test/path", string(body))
+}
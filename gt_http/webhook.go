@@ -0,0 +1,264 @@
+package gt_http
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookParamsKey gin 上下文中保存回调参数的 key
+const webhookParamsKey = "gt_http.webhookParams"
+
+// WebhookConfig 回调校验配置
+type WebhookConfig struct {
+	Signer         SignerConfig  `mapstructure:"signer"`           // 签名配置, 与对方签名方式一致
+	MaxSkew        time.Duration `mapstructure:"max_skew"`         // 时间戳允许的最大偏差, 默认 5m
+	NonceTTL       time.Duration `mapstructure:"nonce_ttl"`        // 随机串保留时间, 默认 MaxSkew 的 2 倍
+	NonceCacheSize int           `mapstructure:"nonce_cache_size"` // 随机串缓存上限, 超出时淘汰最早的, 默认 100000
+	RejectStatus   int           `mapstructure:"reject_status"`    // 校验失败返回的状态码, 默认 401
+	MaxBodySize    int64         `mapstructure:"max_body_size"`    // 请求体大小上限, 超出时返回 413, 默认 DefaultMaxBodySize
+}
+
+// DefaultMaxBodySize 服务端读取请求体的默认大小上限
+const DefaultMaxBodySize = 1 << 20
+
+// WebhookVerifier 回调请求校验: 签名、时间戳窗口和随机串防重放
+type WebhookVerifier struct {
+	config WebhookConfig
+	signer *Signer
+	nonces *NonceCache
+}
+
+// NewWebhookVerifier 创建回调校验器
+// @param config WebhookConfig 校验配置
+func NewWebhookVerifier(config WebhookConfig) *WebhookVerifier {
+	if config.MaxSkew <= 0 {
+		config.MaxSkew = 5 * time.Minute
+	}
+	if config.NonceTTL <= 0 {
+		config.NonceTTL = 2 * config.MaxSkew
+	}
+	if config.NonceCacheSize <= 0 {
+		config.NonceCacheSize = 100000
+	}
+	if config.RejectStatus == 0 {
+		config.RejectStatus = http.StatusUnauthorized
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultMaxBodySize
+	}
+	return &WebhookVerifier{
+		config: config,
+		signer: NewSigner(config.Signer),
+		nonces: NewNonceCache(config.NonceTTL, config.NonceCacheSize),
+	}
+}
+
+// Handler 依次校验签名、时间戳和随机串的 gin 中间件
+func (v *WebhookVerifier) Handler() gin.HandlerFunc {
+	signature, timestamp, nonce := v.VerifySignature(), v.VerifyTimestamp(), v.VerifyNonce()
+	return func(c *gin.Context) {
+		for _, handler := range []gin.HandlerFunc{signature, timestamp, nonce} {
+			if handler(c); c.IsAborted() {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// VerifySignature 校验签名的 gin 中间件
+// 签名从 SignHeader 请求头读取, 未配置时从签名参数读取
+func (v *WebhookVerifier) VerifySignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, ok := v.params(c)
+		if !ok {
+			return
+		}
+		sign := params[v.signer.config.SignParam]
+		if v.signer.config.SignHeader != "" {
+			sign = c.GetHeader(v.signer.config.SignHeader)
+		}
+		expected := v.signer.Sign(params)
+		if sign == "" || subtle.ConstantTimeCompare([]byte(strings.ToLower(sign)), []byte(strings.ToLower(expected))) != 1 {
+			v.reject(c, "签名错误")
+			return
+		}
+	}
+}
+
+// VerifyTimestamp 校验时间戳在 MaxSkew 窗口内的 gin 中间件, 支持秒和毫秒时间戳
+// 未配置 TimestampParam 时不校验
+func (v *WebhookVerifier) VerifyTimestamp() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := v.signer.config.TimestampParam
+		if name == "" {
+			return
+		}
+		params, ok := v.params(c)
+		if !ok {
+			return
+		}
+		timestamp, err := strconv.ParseInt(params[name], 10, 64)
+		if err != nil {
+			v.reject(c, "时间戳错误")
+			return
+		}
+		var t time.Time
+		if timestamp > 1e12 {
+			t = time.UnixMilli(timestamp)
+		} else {
+			t = time.Unix(timestamp, 0)
+		}
+		if skew := time.Since(t); skew > v.config.MaxSkew || skew < -v.config.MaxSkew {
+			v.reject(c, "时间戳过期")
+			return
+		}
+	}
+}
+
+// VerifyNonce 拒绝重复随机串的 gin 中间件, 应在签名校验之后使用
+// 未配置 NonceParam 时不校验
+func (v *WebhookVerifier) VerifyNonce() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := v.signer.config.NonceParam
+		if name == "" {
+			return
+		}
+		params, ok := v.params(c)
+		if !ok {
+			return
+		}
+		nonce := params[name]
+		if nonce == "" {
+			v.reject(c, "缺少随机串")
+			return
+		}
+		if !v.nonces.Add(nonce) {
+			v.reject(c, "重复请求")
+			return
+		}
+	}
+}
+
+// params 解析并缓存回调参数, 请求体读取后会还原以便后续处理函数使用
+func (v *WebhookVerifier) params(c *gin.Context) (map[string]string, bool) {
+	if cached, ok := c.Get(webhookParamsKey); ok {
+		return cached.(map[string]string), true
+	}
+
+	body, err := readBody(c, v.config.MaxBodySize)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			v.abort(c, http.StatusRequestEntityTooLarge, "请求体过大")
+		} else {
+			v.reject(c, "读取请求体失败")
+		}
+		return nil, false
+	}
+	if body != nil {
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	params, err := collectParams(c.Request.URL.Query(), getBodyType(c.GetHeader("Content-Type"), body), body)
+	if err != nil {
+		v.reject(c, "参数解析失败")
+		return nil, false
+	}
+	c.Set(webhookParamsKey, params)
+	return params, true
+}
+
+func (v *WebhookVerifier) reject(c *gin.Context, reason string) {
+	v.abort(c, v.config.RejectStatus, reason)
+}
+
+func (v *WebhookVerifier) abort(c *gin.Context, status int, reason string) {
+	httpLogger.CWarnw(c.Request.Context(), "回调校验失败",
+		"reason", reason,
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"ip", c.ClientIP(),
+	)
+	c.AbortWithStatusJSON(status, gin.H{"msg": reason})
+}
+
+// readBody 读取请求体, 超过 limit 字节时返回 *http.MaxBytesError, 无请求体时返回 nil
+func readBody(c *gin.Context, limit int64) ([]byte, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, nil
+	}
+	return io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+}
+
+// NonceCache 带过期时间和容量上限的随机串缓存, 并发安全
+type NonceCache struct {
+	ttl   time.Duration
+	size  int
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type nonceEntry struct {
+	nonce    string
+	expireAt time.Time
+}
+
+// NewNonceCache 创建随机串缓存
+// @param ttl time.Duration 保留时间
+// @param size int 容量上限, 超出时淘汰最早的
+func NewNonceCache(ttl time.Duration, size int) *NonceCache {
+	return &NonceCache{
+		ttl:   ttl,
+		size:  size,
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+// Add 添加随机串, 已存在且未过期时返回 false
+// @param nonce string 随机串
+func (n *NonceCache) Add(nonce string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	now := time.Now()
+	n.evictExpired(now)
+	if _, ok := n.items[nonce]; ok {
+		return false
+	}
+	for n.size > 0 && n.order.Len() >= n.size {
+		n.remove(n.order.Front())
+	}
+	n.items[nonce] = n.order.PushBack(&nonceEntry{nonce: nonce, expireAt: now.Add(n.ttl)})
+	return true
+}
+
+// Len 获取缓存中的随机串数量
+func (n *NonceCache) Len() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.evictExpired(time.Now())
+	return n.order.Len()
+}
+
+// evictExpired 按插入顺序淘汰过期的随机串, 保留时间固定因此队首最早过期
+func (n *NonceCache) evictExpired(now time.Time) {
+	for e := n.order.Front(); e != nil && !now.Before(e.Value.(*nonceEntry).expireAt); e = n.order.Front() {
+		n.remove(e)
+	}
+}
+
+func (n *NonceCache) remove(e *list.Element) {
+	n.order.Remove(e)
+	delete(n.items, e.Value.(*nonceEntry).nonce)
+}
@@ -0,0 +1,115 @@
+package gt_http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookVerifier(t *testing.T) {
+	observeLogs(t)
+	gin.SetMode(gin.TestMode)
+	signerConfig := DefaultSignerConfig
+	signerConfig.Secret = "secret"
+	signer := NewSigner(signerConfig)
+	verifier := NewWebhookVerifier(WebhookConfig{Signer: signerConfig, MaxSkew: time.Minute})
+
+	engine := gin.New()
+	engine.POST("/callback", verifier.Handler(), func(c *gin.Context) {
+		c.String(http.StatusOK, c.PostForm("order"))
+	})
+
+	post := func(form url.Values) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", ContentTypeForm)
+		engine.ServeHTTP(w, r)
+		return w
+	}
+	signed := func(timestamp time.Time, nonce string) url.Values {
+		form := url.Values{"order": {"o1"}, "timestamp": {strconv.FormatInt(timestamp.Unix(), 10)}, "nonce": {nonce}}
+		params := map[string]string{}
+		for k := range form {
+			params[k] = form.Get(k)
+		}
+		form.Set("sign", signer.Sign(params))
+		return form
+	}
+
+	w := post(signed(time.Now(), "n1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "o1", w.Body.String())
+
+	// 重放
+	w = post(signed(time.Now(), "n1"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "重复请求")
+
+	// 时间戳过期
+	w = post(signed(time.Now().Add(-2*time.Minute), "n2"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "时间戳过期")
+
+	// 签名错误
+	form := signed(time.Now(), "n3")
+	form.Set("order", "o2")
+	w = post(form)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "签名错误")
+
+	// 请求体过大
+	form = signed(time.Now(), "n4")
+	form.Set("padding", strings.Repeat("x", DefaultMaxBodySize))
+	w = post(form)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "请求体过大")
+}
+
+func TestWebhookVerifier_SignedByClient(t *testing.T) {
+	observeLogs(t)
+	gin.SetMode(gin.TestMode)
+	signerConfig := SignerConfig{
+		Algorithm:       SignHmacSha256,
+		Secret:          "secret",
+		TimestampParam:  "ts",
+		TimestampMillis: true,
+		NonceParam:      "nonce",
+		SignHeader:      "X-Signature",
+	}
+	verifier := NewWebhookVerifier(WebhookConfig{Signer: signerConfig})
+	engine := gin.New()
+	engine.POST("/callback", verifier.Handler(), func(c *gin.Context) {
+		var data map[string]any
+		_ = c.ShouldBindJSON(&data)
+		c.JSON(http.StatusOK, data)
+	})
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	client := NewClient(DefaultClientConfig).Use(NewSigner(signerConfig).Middleware())
+	body, err := client.Post(server.URL+"/callback", nil, map[string]any{"amount": 100})
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"amount":100`)
+}
+
+func TestNonceCache(t *testing.T) {
+	cache := NewNonceCache(50*time.Millisecond, 2)
+	assert.True(t, cache.Add("a"))
+	assert.False(t, cache.Add("a"))
+	assert.True(t, cache.Add("b"))
+	assert.True(t, cache.Add("c"))
+	assert.Equal(t, 2, cache.Len())
+	// a 因容量上限被淘汰
+	assert.True(t, cache.Add("a"))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, 0, cache.Len())
+	assert.True(t, cache.Add("b"))
+}
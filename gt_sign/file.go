@@ -0,0 +1,56 @@
+package gt_sign
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+)
+
+// GetReaderMd5String 流式计算 reader 内容的MD5值
+// @param reader io.Reader 数据
+// @return md5Str string MD5值
+func GetReaderMd5String(reader io.Reader) (md5Str string, err error) {
+	return getReaderHash(reader, md5.New())
+}
+
+// GetReaderSha256String 流式计算 reader 内容的SHA256值
+// @param reader io.Reader 数据
+// @return sha256Str string SHA256值
+func GetReaderSha256String(reader io.Reader) (sha256Str string, err error) {
+	return getReaderHash(reader, sha256.New())
+}
+
+// GetFileMd5String 计算文件的MD5值
+// @param path string 文件路径
+// @return md5Str string MD5值
+func GetFileMd5String(path string) (md5Str string, err error) {
+	return getFileHash(path, md5.New())
+}
+
+// GetFileSha256String 计算文件的SHA256值
+// @param path string 文件路径
+// @return sha256Str string SHA256值
+func GetFileSha256String(path string) (sha256Str string, err error) {
+	return getFileHash(path, sha256.New())
+}
+
+func getFileHash(path string, h hash.Hash) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return getReaderHash(file, h)
+}
+
+func getReaderHash(reader io.Reader, h hash.Hash) (string, error) {
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,28 @@
+package gt_sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GetHmacSha256String 获取HMAC-SHA256签名的十六进制字符串
+// @param key []byte 密钥
+// @param bytes []byte 字节数组
+// @return sign string 签名
+func GetHmacSha256String(key []byte, bytes []byte) (sign string) {
+	h := hmac.New(sha256.New, key)
+	h.Write(bytes)
+	sign = hex.EncodeToString(h.Sum(nil))
+	return
+}
+
+// GetSha256String 获取字符串的SHA256值
+// @param bytes []byte 字节数组
+// @return sha256Str string SHA256值
+func GetSha256String(bytes []byte) (sha256Str string) {
+	h := sha256.New()
+	h.Write(bytes)
+	sha256Str = hex.EncodeToString(h.Sum(nil))
+	return
+}
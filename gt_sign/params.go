@@ -0,0 +1,86 @@
+package gt_sign
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetSortedParamString 将参数按key的字典序拼接为 k1=v1&k2=v2 形式的待签名字符串
+// 值为空的参数和 excludeKeys 中的参数不参与拼接
+// @param params map[string]string 参数
+// @param excludeKeys ...string 不参与签名的参数, 如签名字段本身
+// @return str string 待签名字符串
+func GetSortedParamString(params map[string]string, excludeKeys ...string) (str string) {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v == "" || contains(excludeKeys, k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(params[k])
+	}
+	str = sb.String()
+	return
+}
+
+// GetJsonParams 将JSON对象请求体转换为待签名参数, 签名方和验签方需使用同一规则:
+// 字符串取其内容; 数字取请求体中的原文, 如 1.50 为 "1.50"; 布尔值为 "true" / "false"; null 视为空值, 不参与签名;
+// 对象和数组为紧凑JSON, 对象的key按字典序排列, 数字保持原文, 字符串按标准JSON转义但不转义 < > &
+// 如 {"a":1,"b":"x","c":{"z":[1, 2],"y":null}} 转换为 a=1, b=x, c={"y":null,"z":[1,2]}
+// @param body []byte JSON对象, 为空时返回空参数
+// @return params map[string]string 参数
+// @return err error 请求体不是JSON对象时返回错误
+func GetJsonParams(body []byte) (params map[string]string, err error) {
+	params = map[string]string{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	object := map[string]any{}
+	if err = decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	for k, v := range object {
+		switch value := v.(type) {
+		case nil:
+		case string:
+			params[k] = value
+		case json.Number:
+			params[k] = value.String()
+		case bool:
+			params[k] = strconv.FormatBool(value)
+		default:
+			buf := &bytes.Buffer{}
+			encoder := json.NewEncoder(buf)
+			encoder.SetEscapeHTML(false)
+			if err = encoder.Encode(value); err != nil {
+				return nil, err
+			}
+			params[k] = strings.TrimSuffix(buf.String(), "\n")
+		}
+	}
+	return
+}
+
+func contains(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package slog
+
+import (
+	"bufio"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"go.uber.org/zap/zapcore"
+)
+
+// Overflow policies of AsyncConfig
+const (
+	AsyncBlock      = "block"       // wait for space, no line is lost
+	AsyncDropOldest = "drop_oldest" // discard the oldest queued line
+	AsyncDropNewest = "drop_newest" // discard the incoming line
+)
+
+type AsyncConfig struct {
+	Enable        bool          `mapstructure:"enable"`
+	BufferSize    int           `mapstructure:"buffer_size"`    // max queued lines, defaults to 8192
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // max time a line stays buffered, defaults to 1s
+	Policy        string        `mapstructure:"policy"`         // block, drop_oldest or drop_newest when the queue is full, defaults to block
+}
+
+// AsyncStats is a snapshot of the counters of an AsyncWriter.
+type AsyncStats struct {
+	Queued  int    // lines waiting in the queue
+	Written uint64 // lines handed to the underlying writer
+	Dropped uint64 // lines discarded by the overflow policy or written after Close
+}
+
+// AsyncWriter is a zapcore.WriteSyncer queueing lines in a bounded ring buffer
+// and writing them to the underlying writer from a single goroutine.
+// Sync and Close block until every queued line has been written and flushed.
+type AsyncWriter struct {
+	config  AsyncConfig
+	out     zapcore.WriteSyncer
+	buf     *bufio.Writer
+	mu      sync.Mutex
+	notFull *sync.Cond
+	ring    [][]byte
+	head    int
+	count   int
+	closed  bool
+	written atomic.Uint64
+	dropped atomic.Uint64
+
+	notify  chan struct{}
+	syncReq chan chan error
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewAsyncWriter starts an AsyncWriter writing to out. It panics if config.Policy is unknown.
+func NewAsyncWriter(out zapcore.WriteSyncer, config AsyncConfig) *AsyncWriter {
+	if err := checkAsyncPolicy(config.Policy); err != nil {
+		panic(err)
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 8192
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.Policy == "" {
+		config.Policy = AsyncBlock
+	}
+	w := &AsyncWriter{
+		config:  config,
+		out:     out,
+		buf:     bufio.NewWriterSize(out, 256<<10),
+		ring:    make([][]byte, config.BufferSize),
+		notify:  make(chan struct{}, 1),
+		syncReq: make(chan chan error),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	w.notFull = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, zap reuses the buffer after Write returns.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	line := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	for w.count == len(w.ring) && !w.closed && w.config.Policy == AsyncBlock {
+		w.notFull.Wait()
+	}
+	switch {
+	case w.closed:
+		w.mu.Unlock()
+		w.dropped.Add(1)
+		return len(p), nil
+	case w.count == len(w.ring) && w.config.Policy == AsyncDropNewest:
+		w.mu.Unlock()
+		w.dropped.Add(1)
+		return len(p), nil
+	case w.count == len(w.ring) && w.config.Policy == AsyncDropOldest:
+		w.ring[w.head] = nil
+		w.head = (w.head + 1) % len(w.ring)
+		w.count--
+		w.dropped.Add(1)
+	}
+	w.ring[(w.head+w.count)%len(w.ring)] = line
+	w.count++
+	w.mu.Unlock()
+
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Sync waits until the queued lines are written and flushed to the underlying writer.
+func (w *AsyncWriter) Sync() error {
+	reply := make(chan error, 1)
+	select {
+	case w.syncReq <- reply:
+		return <-reply
+	case <-w.stopped:
+		return nil
+	}
+}
+
+// Close flushes the queued lines and stops the writer goroutine. It does not close the underlying writer.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+	return nil
+}
+
+// Stats returns the current counters.
+func (w *AsyncWriter) Stats() AsyncStats {
+	w.mu.Lock()
+	queued := w.count
+	w.mu.Unlock()
+	return AsyncStats{Queued: queued, Written: w.written.Load(), Dropped: w.dropped.Load()}
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.notify:
+			w.drain()
+		case <-ticker.C:
+			w.drain()
+			w.flush()
+		case reply := <-w.syncReq:
+			w.drain()
+			w.flush()
+			reply <- w.out.Sync()
+		case <-w.done:
+			w.drain()
+			w.flush()
+			return
+		}
+	}
+}
+
+// drain writes the queued lines to the buffer, the buffer writes through when full.
+func (w *AsyncWriter) drain() {
+	for {
+		w.mu.Lock()
+		if w.count == 0 {
+			w.mu.Unlock()
+			return
+		}
+		lines := make([][]byte, 0, w.count)
+		for ; w.count > 0; w.count-- {
+			lines = append(lines, w.ring[w.head])
+			w.ring[w.head] = nil
+			w.head = (w.head + 1) % len(w.ring)
+		}
+		w.notFull.Broadcast()
+		w.mu.Unlock()
+
+		for _, line := range lines {
+			if _, err := w.buf.Write(line); err != nil {
+				loggers.DefaultError(fmt.Sprintf("async log write failed: %v", err))
+				w.buf.Reset(w.out)
+			}
+		}
+		w.written.Add(uint64(len(lines)))
+	}
+}
+
+func (w *AsyncWriter) flush() {
+	if err := w.buf.Flush(); err != nil {
+		loggers.DefaultError(fmt.Sprintf("async log flush failed: %v", err))
+		w.buf.Reset(w.out)
+	}
+}
+
+// GetAsyncStats returns the counters of the async file writers by log file name, empty if async mode is off.
+func GetAsyncStats() map[string]AsyncStats {
+	stats := map[string]AsyncStats{}
+	if out := outputs.Load(); out != nil {
+		for filename, w := range out.async {
+			stats[filename] = w.Stats()
+		}
+	}
+	return stats
+}
+
+// newAsyncFileSyncer wraps a file syncer with an AsyncWriter. The returned close function
+// flushes the queue before closing the file.
+func (out *coreOutputs) newAsyncFileSyncer(config AsyncConfig, filename string, syncer zapcore.WriteSyncer, closeFunc func() (err error)) (zapcore.WriteSyncer, func() (err error)) {
+	w := NewAsyncWriter(syncer, config)
+	out.async[filename] = w
+	return w, func() (err error) {
+		_ = w.Close()
+		return closeFunc()
+	}
+}
+
+func checkAsyncPolicy(policy string) error {
+	switch policy {
+	case "", AsyncBlock, AsyncDropOldest, AsyncDropNewest:
+		return nil
+	}
+	return fmt.Errorf("async: unknown policy %q", policy)
+}
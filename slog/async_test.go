@@ -0,0 +1,155 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+// gatedWriter blocks every Write until the gate is opened.
+type gatedWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	gate    chan struct{}
+	entered chan struct{}
+	once    sync.Once
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{}), entered: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.entered) })
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) Sync() error { return nil }
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// blockConsumer writes "a" and makes the writer goroutine block on the underlying writer,
+// so the following writes stay in the queue.
+func blockConsumer(t *testing.T, policy string) (*AsyncWriter, *gatedWriter) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, AsyncConfig{BufferSize: 2, FlushInterval: time.Hour, Policy: policy})
+	_, _ = w.Write([]byte("a"))
+	go func() { _ = w.Sync() }()
+	select {
+	case <-out.entered:
+	case <-time.After(time.Second):
+		t.Fatal("writer goroutine did not flush")
+	}
+	return w, out
+}
+
+func TestAsyncWriter_Policy(t *testing.T) {
+	t.Run("drop newest", func(t *testing.T) {
+		w, out := blockConsumer(t, AsyncDropNewest)
+		for _, line := range []string{"b", "c", "d"} {
+			_, _ = w.Write([]byte(line))
+		}
+		assert.Equal(t, AsyncStats{Queued: 2, Written: 1, Dropped: 1}, w.Stats())
+		close(out.gate)
+		assert.NoError(t, w.Close())
+		assert.Equal(t, "abc", out.String())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		w, out := blockConsumer(t, AsyncDropOldest)
+		for _, line := range []string{"b", "c", "d"} {
+			_, _ = w.Write([]byte(line))
+		}
+		assert.Equal(t, uint64(1), w.Stats().Dropped)
+		close(out.gate)
+		assert.NoError(t, w.Close())
+		assert.Equal(t, "acd", out.String())
+	})
+
+	t.Run("block", func(t *testing.T) {
+		w, out := blockConsumer(t, AsyncBlock)
+		_, _ = w.Write([]byte("b"))
+		_, _ = w.Write([]byte("c"))
+		written := make(chan struct{})
+		go func() {
+			_, _ = w.Write([]byte("d"))
+			close(written)
+		}()
+		select {
+		case <-written:
+			t.Fatal("write should block while the queue is full")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(out.gate)
+		<-written
+		assert.NoError(t, w.Close())
+		assert.Equal(t, "abcd", out.String())
+		assert.Equal(t, AsyncStats{Written: 4}, w.Stats())
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		assert.Panics(t, func() { NewAsyncWriter(zapcore.AddSync(&bytes.Buffer{}), AsyncConfig{Policy: "drop-oldest"}) })
+	})
+}
+
+func TestAsyncWriter_Flush(t *testing.T) {
+	out := newGatedWriter()
+	close(out.gate)
+
+	w := NewAsyncWriter(out, AsyncConfig{FlushInterval: 20 * time.Millisecond})
+	_, _ = w.Write([]byte("interval\n"))
+	assert.Eventually(t, func() bool { return out.String() == "interval\n" }, time.Second, 5*time.Millisecond)
+
+	_, _ = w.Write([]byte("close\n"))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "interval\nclose\n", out.String())
+
+	// writes after Close are dropped
+	_, _ = w.Write([]byte("late\n"))
+	assert.NoError(t, w.Sync())
+	assert.Equal(t, uint64(1), w.Stats().Dropped)
+	assert.Equal(t, "interval\nclose\n", out.String())
+}
+
+func TestInit_Async(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true, Async: &AsyncConfig{Enable: true, FlushInterval: time.Hour}})
+	assert.Contains(t, GetAsyncStats(), OutputLogFile)
+
+	for i := 0; i < 100; i++ {
+		CInfo(context.Background(), "async line %d", i)
+	}
+	CError(context.Background(), "async error")
+	Close()
+
+	assert.Empty(t, GetAsyncStats())
+	assert.Contains(t, readLog(t, dir, OutputLogFile), "async line 99")
+	assert.Contains(t, readLog(t, dir, ErrorLogFile), "async error")
+}
+
+func TestReload_InvalidAsync(t *testing.T) {
+	dir := t.TempDir()
+	async := &AsyncConfig{Enable: true, FlushInterval: time.Hour}
+	Init(LogConfig{Dir: dir, File: true, Async: async})
+	defer Close()
+	CInfo(context.Background(), "before reload")
+
+	assert.Error(t, Reload(LogConfig{Dir: dir, File: true, Async: &AsyncConfig{Enable: true, Policy: "drop-oldest"}}))
+	// the writers of a failed reload are never reported, the current ones still are
+	assert.Error(t, Reload(LogConfig{Dir: t.TempDir(), File: true, Async: async, Sinks: []SinkConfig{{Type: "kafka", Address: "127.0.0.1:9092"}}}))
+	stats := GetAsyncStats()
+	assert.Len(t, stats, 3)
+	assert.Equal(t, 1, stats[OutputLogFile].Queued+int(stats[OutputLogFile].Written))
+}
@@ -18,15 +18,19 @@ package slog
 // FatalLevel = 5
 
 type LogConfig struct {
-	Name         string        `mapstructure:"name"`  // logger name, can be service name
-	Level        int           `mapstructure:"level"` // zapcore/level.go
-	Dir          string        `mapstructure:"dir"`
-	Console      bool          `mapstructure:"console"`
-	File         bool          `mapstructure:"file"`
-	RotateConfig *RotateConfig `mapstructure:"rotate"`
-	DebugRotate  *RotateConfig `mapstructure:"debug_rotate"`  // using rotate config, if nil. Debug log will not be collected by log service
-	OutputRotate *RotateConfig `mapstructure:"output_rotate"` // using rotate config, if nil
-	ErrorRotate  *RotateConfig `mapstructure:"error_rotate"`  // using rotate config, if nil
+	Name         string          `mapstructure:"name"`          // logger name, can be service name
+	Level        int             `mapstructure:"level"`         // zapcore/level.go, can be changed at runtime by SetLevel, Reload keeps the change until this value changes
+	LoggerLevels map[string]int  `mapstructure:"logger_levels"` // level overrides by SLogger name, e.g. {"sys": -1}
+	Dir          string          `mapstructure:"dir"`
+	Console      bool            `mapstructure:"console"`
+	File         bool            `mapstructure:"file"`
+	RotateConfig *RotateConfig   `mapstructure:"rotate"`
+	DebugRotate  *RotateConfig   `mapstructure:"debug_rotate"`  // using rotate config, if nil. Debug log will not be collected by log service
+	OutputRotate *RotateConfig   `mapstructure:"output_rotate"` // using rotate config, if nil
+	ErrorRotate  *RotateConfig   `mapstructure:"error_rotate"`  // using rotate config, if nil
+	Async        *AsyncConfig    `mapstructure:"async"`         // write log files asynchronously, if enabled
+	Sampling     *SamplingConfig `mapstructure:"sampling"`      // sample repeated entries, if enabled
+	Sinks        []SinkConfig    `mapstructure:"sinks"`         // remote outputs besides console and files
 }
 
 type RotateConfig struct {
@@ -0,0 +1,103 @@
+package slog
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var errorOutput = zapcore.Lock(os.Stderr)
+
+// swapRoot holds the current core shared by every logger created from it.
+// Writes hold the read lock, so once swap returns no write can reach the old core
+// and its syncers can be closed safely.
+type swapRoot struct {
+	mu   sync.RWMutex
+	core atomic.Pointer[zapcore.Core]
+}
+
+func newSwapRoot(core zapcore.Core) *swapRoot {
+	root := &swapRoot{}
+	root.core.Store(&core)
+	return root
+}
+
+func (r *swapRoot) load() zapcore.Core {
+	return *r.core.Load()
+}
+
+// swap replaces the current core and returns the old one.
+func (r *swapRoot) swap(core zapcore.Core) zapcore.Core {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.core.Swap(&core)
+}
+
+// swapCore is a zapcore.Core delegating to the current core of its root.
+// Fields added by With are applied to whichever core is current at write time.
+type swapCore struct {
+	root    *swapRoot
+	fields  []zapcore.Field
+	derived atomic.Pointer[derivedCore]
+}
+
+// derivedCore caches base.With(fields) until the root core is swapped.
+// base is compared by pointer since tee cores are not comparable.
+type derivedCore struct {
+	base *zapcore.Core
+	core zapcore.Core
+}
+
+func newSwapCore(root *swapRoot) *swapCore {
+	return &swapCore{root: root}
+}
+
+func (c *swapCore) current() zapcore.Core {
+	base := c.root.core.Load()
+	if len(c.fields) == 0 {
+		return *base
+	}
+	if derived := c.derived.Load(); derived != nil && derived.base == base {
+		return derived.core
+	}
+	core := (*base).With(c.fields)
+	c.derived.Store(&derivedCore{base: base, core: core})
+	return core
+}
+
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.root.load().Enabled(level)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	return &swapCore{root: c.root, fields: all}
+}
+
+func (c *swapCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *swapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	c.root.mu.RLock()
+	defer c.root.mu.RUnlock()
+	// the current core re-checks the entry so each output applies its own level range
+	if ce := c.current().Check(entry, nil); ce != nil {
+		ce.ErrorOutput = errorOutput
+		ce.Write(fields...)
+	}
+	return nil
+}
+
+func (c *swapCore) Sync() error {
+	c.root.mu.RLock()
+	defer c.root.mu.RUnlock()
+	return c.root.load().Sync()
+}
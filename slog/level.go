@@ -0,0 +1,110 @@
+package slog
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"go.uber.org/zap/zapcore"
+)
+
+// SetLevel changes the global log level at runtime.
+func SetLevel(level zapcore.Level) {
+	loggers.Level.SetLevel(level)
+}
+
+// GetLevel returns the global log level.
+func GetLevel() zapcore.Level {
+	return loggers.Level.Level()
+}
+
+// SetLoggerLevel overrides the level of the SLoggers with the given name,
+// e.g. "sys" for NewSLogger("[sys] %s"). The override can be lower or higher than the global level.
+func SetLoggerLevel(name string, level zapcore.Level) {
+	loggers.SetNameLevel(name, level)
+}
+
+// UnsetLoggerLevel removes the level override of the SLoggers with the given name.
+func UnsetLoggerLevel(name string) {
+	loggers.UnsetNameLevel(name)
+}
+
+// GetLoggerLevels returns the level overrides by SLogger name.
+func GetLoggerLevels() map[string]zapcore.Level {
+	return loggers.GetNameLevels()
+}
+
+type levelPayload struct {
+	Level   string            `json:"level"`
+	Logger  string            `json:"logger,omitempty"`
+	Loggers map[string]string `json:"loggers,omitempty"`
+}
+
+// LevelHandler returns an http.Handler to read and change log levels. Mount it in gin with gin.WrapH.
+//
+//	GET                                       -> {"level":"info","loggers":{"sys":"debug"}}
+//	PUT {"level":"debug"}                     -> change the global level
+//	PUT {"logger":"sys","level":"debug"}      -> override the level of SLogger "sys"
+//	PUT {"logger":"sys","level":""}           -> remove the override
+//
+// PUT and POST also accept the level and logger query parameters instead of a JSON body.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			if err := changeLevel(r); err != nil {
+				writeLevelJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			writeLevelJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+
+		payload := levelPayload{Level: GetLevel().String(), Loggers: map[string]string{}}
+		for name, level := range GetLoggerLevels() {
+			payload.Loggers[name] = level.String()
+		}
+		writeLevelJSON(w, http.StatusOK, payload)
+	})
+}
+
+func changeLevel(r *http.Request) error {
+	var payload levelPayload
+	query := r.URL.Query()
+	if query.Has("level") {
+		payload.Level, payload.Logger = query.Get("level"), query.Get("logger")
+	} else if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return errors.New("invalid request body: " + err.Error())
+	}
+
+	if payload.Logger != "" && payload.Level == "" {
+		UnsetLoggerLevel(payload.Logger)
+		CWarnw(r.Context(), "log level override removed", "logger", payload.Logger)
+		return nil
+	}
+	if payload.Level == "" {
+		return errors.New("level is required")
+	}
+	level, err := zapcore.ParseLevel(payload.Level)
+	if err != nil {
+		return err
+	}
+	if payload.Logger != "" {
+		SetLoggerLevel(payload.Logger, level)
+		CWarnw(r.Context(), "log level override changed", "logger", payload.Logger, "level", level.String())
+	} else {
+		SetLevel(level)
+		CWarnw(r.Context(), "log level changed", "level", level.String())
+	}
+	return nil
+}
+
+func writeLevelJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,108 @@
+package slog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func setupLevelTest(t *testing.T) *observer.ObservedLogs {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	loggers.Logger_2 = zap.New(loggers.NewLevelCore(core)).Sugar()
+	SetLevel(zapcore.InfoLevel)
+	t.Cleanup(func() {
+		loggers.Logger_2 = nil
+		SetLevel(zapcore.InfoLevel)
+		loggers.SetNameLevels(nil)
+	})
+	return recorded
+}
+
+func TestSetLevel(t *testing.T) {
+	recorded := setupLevelTest(t)
+	ctx := context.Background()
+
+	CDebug(ctx, "hidden")
+	CInfo(ctx, "shown")
+	assert.Equal(t, 1, recorded.Len())
+
+	SetLevel(zapcore.DebugLevel)
+	assert.Equal(t, zapcore.DebugLevel, GetLevel())
+	CDebug(ctx, "debug shown")
+	assert.Equal(t, 2, recorded.Len())
+
+	SetLevel(zapcore.ErrorLevel)
+	CWarn(ctx, "hidden")
+	CError(ctx, "error shown")
+	assert.Equal(t, 3, recorded.Len())
+}
+
+func TestSetLoggerLevel(t *testing.T) {
+	recorded := setupLevelTest(t)
+	ctx := context.Background()
+	sys := NewSLogger("[sys] %s")
+	db := NewSLogger("[db] %s")
+
+	SetLoggerLevel("sys", zapcore.DebugLevel)
+	SetLoggerLevel("db", zapcore.ErrorLevel)
+	assert.Equal(t, map[string]zapcore.Level{"sys": zapcore.DebugLevel, "db": zapcore.ErrorLevel}, GetLoggerLevels())
+
+	sys.CDebug(ctx, "sys debug")
+	db.CWarn(ctx, "db warn")
+	db.CError(ctx, "db error")
+	CDebug(ctx, "global debug")
+	messages := []string{}
+	for _, entry := range recorded.TakeAll() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"[sys] sys debug", "[db] db error"}, messages)
+
+	UnsetLoggerLevel("sys")
+	sys.CDebug(ctx, "sys debug")
+	assert.Equal(t, 0, recorded.Len())
+}
+
+func TestLevelHandler(t *testing.T) {
+	setupLevelTest(t)
+	handler := LevelHandler()
+	serve := func(method string, target string, body string) (int, map[string]any) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		result := map[string]any{}
+		_ = json.Unmarshal(recorder.Body.Bytes(), &result)
+		return recorder.Code, result
+	}
+
+	code, result := serve(http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "info", result["level"])
+
+	code, result = serve(http.MethodPut, "/", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "debug", result["level"])
+	assert.Equal(t, zapcore.DebugLevel, GetLevel())
+
+	code, result = serve(http.MethodPost, "/?logger=sys&level=warn", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"sys": "warn"}, result["loggers"])
+
+	code, _ = serve(http.MethodPut, "/", `{"logger":"sys","level":""}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, GetLoggerLevels())
+
+	code, result = serve(http.MethodPut, "/", `{"level":"verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.NotEmpty(t, result["error"])
+
+	code, _ = serve(http.MethodDelete, "/", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
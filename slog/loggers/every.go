@@ -0,0 +1,109 @@
+package loggers
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SuppressedKey is the field added to a rate limited entry with the number of entries
+// suppressed at the same call site since the previous one.
+const SuppressedKey = "suppressed"
+
+type callSite struct {
+	mu         sync.Mutex
+	next       time.Time
+	suppressed int
+}
+
+var callSites sync.Map // caller pc -> *callSite
+
+// resetCallSites forgets the state of every call site, for tests.
+func resetCallSites() {
+	callSites.Range(func(key, _ any) bool {
+		callSites.Delete(key)
+		return true
+	})
+}
+
+// allowCallSite rate limits the caller of the *Every function to one entry per interval.
+func allowCallSite(interval time.Duration) (ok bool, suppressed int) {
+	pc, _, _, _ := runtime.Caller(2)
+	value, loaded := callSites.Load(pc)
+	if !loaded {
+		value, _ = callSites.LoadOrStore(pc, &callSite{})
+	}
+	site := value.(*callSite)
+
+	now := time.Now()
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	if now.Before(site.next) {
+		site.suppressed++
+		return false, 0
+	}
+	site.next = now.Add(interval)
+	suppressed, site.suppressed = site.suppressed, 0
+	return true, suppressed
+}
+
+func withSuppressed(keysAndValues []any, suppressed int) []any {
+	if suppressed == 0 {
+		return keysAndValues
+	}
+	kvs := make([]any, 0, len(keysAndValues)+2)
+	kvs = append(kvs, keysAndValues...)
+	return append(kvs, SuppressedKey, suppressed)
+}
+
+// CLogEvery logs at most once per interval from the same call site. The next entry logged
+// carries the number of suppressed entries in the "suppressed" field.
+func CLogEvery(ctx context.Context, interval time.Duration, level zapcore.Level, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, "", level, fmt.Sprintf(template, args...), withSuppressed(withTemplate(nil, template, args), suppressed)...)
+	}
+}
+func CInfoEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, "", zapcore.InfoLevel, fmt.Sprintf(template, args...), withSuppressed(withTemplate(nil, template, args), suppressed)...)
+	}
+}
+func CWarnEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, "", zapcore.WarnLevel, fmt.Sprintf(template, args...), withSuppressed(withTemplate(nil, template, args), suppressed)...)
+	}
+}
+func CErrorEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, "", zapcore.ErrorLevel, fmt.Sprintf(template, args...), withSuppressed(withTemplate(nil, template, args), suppressed)...)
+	}
+}
+
+// CLogEvery logs at most once per interval from the same call site, see CLogEvery.
+func (s *SLogger) CLogEvery(ctx context.Context, interval time.Duration, level zapcore.Level, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, s.GetName(), level, s.GetMsg(fmt.Sprintf(template, args...)), withSuppressed(s.getTemplateKeysAndValues(template, args), suppressed)...)
+	}
+}
+
+func (s *SLogger) CInfoEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, s.GetName(), zapcore.InfoLevel, s.GetMsg(fmt.Sprintf(template, args...)), withSuppressed(s.getTemplateKeysAndValues(template, args), suppressed)...)
+	}
+}
+
+func (s *SLogger) CWarnEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, s.GetName(), zapcore.WarnLevel, s.GetMsg(fmt.Sprintf(template, args...)), withSuppressed(s.getTemplateKeysAndValues(template, args), suppressed)...)
+	}
+}
+
+func (s *SLogger) CErrorEvery(ctx context.Context, interval time.Duration, template string, args ...interface{}) {
+	if ok, suppressed := allowCallSite(interval); ok {
+		logWithLevelAndContext(ctx, 0, s.GetName(), zapcore.ErrorLevel, s.GetMsg(fmt.Sprintf(template, args...)), withSuppressed(s.getTemplateKeysAndValues(template, args), suppressed)...)
+	}
+}
@@ -0,0 +1,42 @@
+package loggers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCInfoEvery(t *testing.T) {
+	recorded, _ := setupTestLogger(t)
+	resetCallSites()
+
+	ctx := context.Background()
+	logger := NewSLogger("[every] %s")
+	interval := 50 * time.Millisecond
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 10; i++ {
+			CInfoEvery(ctx, interval, "round %d", round)
+			logger.CWarnEvery(ctx, interval, "logger round %d", round)
+		}
+		time.Sleep(interval)
+	}
+
+	entries := recorded.AllUntimed()
+	if !assert.Len(t, entries, 4) {
+		return
+	}
+	for _, entry := range entries {
+		suppressed, ok := entry.ContextMap()[SuppressedKey]
+		switch entry.Message {
+		case "round 0", "[every] logger round 0":
+			assert.False(t, ok, entry.Message)
+		case "round 1", "[every] logger round 1":
+			// each call site counts its own suppressed entries
+			assert.Equal(t, int64(9), suppressed, entry.Message)
+		default:
+			t.Errorf("unexpected entry %q", entry.Message)
+		}
+	}
+}
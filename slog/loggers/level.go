@@ -0,0 +1,113 @@
+package loggers
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level is the global runtime level. Cores wrapped by NewLevelCore drop entries below it.
+var Level = zap.NewAtomicLevel()
+
+var (
+	nameLevelsMu sync.Mutex
+	nameLevels   atomic.Pointer[map[string]zapcore.Level] // copy-on-write, read on every SLogger call
+)
+
+// SetNameLevel overrides the level of the SLoggers with the given name.
+// The override takes precedence over the global Level in both directions.
+func SetNameLevel(name string, level zapcore.Level) {
+	updateNameLevels(func(levels map[string]zapcore.Level) {
+		levels[name] = level
+	})
+}
+
+// UnsetNameLevel removes the level override of the SLoggers with the given name.
+func UnsetNameLevel(name string) {
+	updateNameLevels(func(levels map[string]zapcore.Level) {
+		delete(levels, name)
+	})
+}
+
+// SetNameLevels replaces all per-name level overrides.
+func SetNameLevels(levels map[string]zapcore.Level) {
+	updateNameLevels(func(current map[string]zapcore.Level) {
+		clear(current)
+		for name, level := range levels {
+			current[name] = level
+		}
+	})
+}
+
+// GetNameLevels returns a copy of the per-name level overrides.
+func GetNameLevels() map[string]zapcore.Level {
+	levels := map[string]zapcore.Level{}
+	if current := nameLevels.Load(); current != nil {
+		for name, level := range *current {
+			levels[name] = level
+		}
+	}
+	return levels
+}
+
+func getNameLevel(name string) (zapcore.Level, bool) {
+	current := nameLevels.Load()
+	if current == nil || name == "" {
+		return 0, false
+	}
+	level, ok := (*current)[name]
+	return level, ok
+}
+
+func updateNameLevels(update func(levels map[string]zapcore.Level)) {
+	nameLevelsMu.Lock()
+	defer nameLevelsMu.Unlock()
+	levels := GetNameLevels()
+	update(levels)
+	if len(levels) == 0 {
+		nameLevels.Store(nil)
+		return
+	}
+	nameLevels.Store(&levels)
+}
+
+// GetTemplateName derives a logger name from an SLogger template, e.g. "[sys] %s" -> "sys".
+func GetTemplateName(template string) string {
+	name, _, _ := strings.Cut(template, "%")
+	return strings.Trim(name, " []:-|")
+}
+
+// NewLevelCore wraps core so that entries below the global Level are dropped.
+func NewLevelCore(core zapcore.Core) zapcore.Core {
+	return &levelCore{Core: core}
+}
+
+type levelCore struct {
+	zapcore.Core
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return Level.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields)}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !Level.Enabled(entry.Level) {
+		return ce
+	}
+	return c.Core.Check(entry, ce)
+}
+
+// bypassLevelCore unwraps levelCore so an SLogger name override can log below the global Level.
+var bypassLevelCore = zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+	if c, ok := core.(*levelCore); ok {
+		return c.Core
+	}
+	return core
+})
@@ -35,121 +35,159 @@ func DefaultPanicw(msg string, keysAndValues ...interface{}) {
 }
 
 var Log = func(lvl zapcore.Level, args ...interface{}) {
-	DefaultPrint(args)
+	DefaultPrint(args...)
 }
 var Logw = func(lvl zapcore.Level, msg string, keysAndValues ...interface{}) {
 	DefaultPrintw(msg, keysAndValues)
 }
 var Logf = func(lvl zapcore.Level, template string, args ...interface{}) {
-	DefaultPrintf(template, args)
+	DefaultPrintf(template, args...)
 }
 var Logln = func(lvl zapcore.Level, args ...interface{}) {
-	DefaultPrintln(args)
+	DefaultPrintln(args...)
 }
 
 func CLog(ctx context.Context, level zapcore.Level, extra_skip int, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, extra_skip, level, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, extra_skip, "", level, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CLogln(ctx context.Context, level zapcore.Level, extra_skip int, args ...interface{}) {
-	logWithLevelAndContext(ctx, extra_skip, level, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, extra_skip, "", level, fmt.Sprint(args...))
 }
 func CLogw(ctx context.Context, level zapcore.Level, extra_skip int, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, extra_skip, level, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, extra_skip, "", level, msg, keysAndValues...)
 }
 func CDebug(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DebugLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.DebugLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CDebugln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DebugLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.DebugLevel, fmt.Sprint(args...))
 }
 func CDebugw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DebugLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.DebugLevel, msg, keysAndValues...)
 }
 func CInfo(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.InfoLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.InfoLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CInfoln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.InfoLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.InfoLevel, fmt.Sprint(args...))
 }
 func CInfow(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.InfoLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.InfoLevel, msg, keysAndValues...)
 }
 func CWarn(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.WarnLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.WarnLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CWarnln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.WarnLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.WarnLevel, fmt.Sprint(args...))
 }
 func CWarnw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.WarnLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.WarnLevel, msg, keysAndValues...)
 }
 func CError(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.ErrorLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.ErrorLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CErrorln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.ErrorLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.ErrorLevel, fmt.Sprint(args...))
 }
 func CErrorw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.ErrorLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.ErrorLevel, msg, keysAndValues...)
 }
 func CDPanic(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DPanicLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.DPanicLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CDPanicln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DPanicLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.DPanicLevel, fmt.Sprint(args...))
 }
 func CDPanicw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.DPanicLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.DPanicLevel, msg, keysAndValues...)
 }
 func CPanic(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.PanicLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.PanicLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CPanicln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.PanicLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.PanicLevel, fmt.Sprint(args...))
 }
 func CPanicw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.PanicLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.PanicLevel, msg, keysAndValues...)
 }
 func CFatal(ctx context.Context, template string, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.FatalLevel, fmt.Sprintf(template, args...))
+	logWithLevelAndContext(ctx, 0, "", zap.FatalLevel, fmt.Sprintf(template, args...), withTemplate(nil, template, args)...)
 }
 func CFatalln(ctx context.Context, args ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.FatalLevel, fmt.Sprint(args...))
+	logWithLevelAndContext(ctx, 0, "", zap.FatalLevel, fmt.Sprint(args...))
 }
 func CFatalw(ctx context.Context, msg string, keysAndValues ...interface{}) {
-	logWithLevelAndContext(ctx, 0, zap.FatalLevel, msg, keysAndValues...)
+	logWithLevelAndContext(ctx, 0, "", zap.FatalLevel, msg, keysAndValues...)
 }
 
-func logWithLevelAndContext(ctx context.Context, extra_skip int, level zapcore.Level, msg string, keysAndValues ...interface{}) {
+// logWithLevelAndContext logs with the context fields. name is the SLogger name used to look up
+// level overrides, empty for the package level functions.
+func logWithLevelAndContext(ctx context.Context, extra_skip int, name string, level zapcore.Level, msg string, keysAndValues ...interface{}) {
+	logger := Logger_2
+	if nameLevel, ok := getNameLevel(name); ok {
+		if level < nameLevel && level < zapcore.DPanicLevel {
+			return
+		}
+		if logger != nil && !Level.Enabled(level) {
+			logger = logger.WithOptions(bypassLevelCore)
+		}
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	kvs := log_context.GetLogContext(ctx)
 	kvs = append(kvs, keysAndValues...)
-	if Logger_2 == nil {
+	if logger == nil {
+		kvs = withoutTemplate(kvs)
 		if level == zapcore.PanicLevel {
 			DefaultPanicw(msg, kvs...)
 		} else {
 			DefaultPrintw(msg, kvs...)
 		}
 	} else if extra_skip == 0 {
-		Logger_2.Logw(level, msg, kvs...)
+		logger.Logw(level, msg, kvs...)
 	} else {
-		Logger_2.WithOptions(zap.AddCallerSkip(extra_skip)).Logw(level, msg, kvs...)
+		logger.WithOptions(zap.AddCallerSkip(extra_skip)).Logw(level, msg, kvs...)
 	}
 }
 
 func UsingDefaultLogger() {
 	Log = func(_ zapcore.Level, args ...interface{}) {
-		DefaultPrint(args)
+		DefaultPrint(args...)
 	}
 	Logw = func(_ zapcore.Level, msg string, keysAndValues ...interface{}) {
 		DefaultPrintw(msg, keysAndValues)
 	}
 	Logf = func(_ zapcore.Level, template string, args ...interface{}) {
-		DefaultPrintf(template, args)
+		DefaultPrintf(template, args...)
 	}
 	Logln = func(_ zapcore.Level, args ...interface{}) {
-		DefaultPrintln(args)
+		DefaultPrintln(args...)
+	}
+}
+
+// TemplateKey is the key of the field carrying the template of a formatted entry, so that
+// sampling groups the entries by template instead of by formatted message. Encoders skip it.
+const TemplateKey = "msg_template"
+
+// withTemplate adds the template of a formatted entry to keysAndValues.
+func withTemplate(keysAndValues []any, template string, args []any) []any {
+	if len(args) == 0 {
+		return keysAndValues
+	}
+	kvs := make([]any, 0, len(keysAndValues)+1)
+	kvs = append(kvs, keysAndValues...)
+	return append(kvs, zap.Field{Key: TemplateKey, Type: zapcore.SkipType, String: template})
+}
+
+// withoutTemplate removes the template field for the default loggers, which print every value.
+func withoutTemplate(keysAndValues []any) []any {
+	kvs := keysAndValues[:0:0]
+	for _, kv := range keysAndValues {
+		if field, ok := kv.(zap.Field); ok && field.Key == TemplateKey && field.Type == zapcore.SkipType {
+			continue
+		}
+		kvs = append(kvs, kv)
 	}
+	return kvs
 }
@@ -47,7 +47,7 @@ func TestLogWithLevelAndContext(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			recorded.TakeAll() // Clear previous logs before each test case
 			ctx := context.Background()
-			logWithLevelAndContext(ctx, 0, tt.level, tt.msg, tt.keysAndValues...)
+			logWithLevelAndContext(ctx, 0, "", tt.level, tt.msg, tt.keysAndValues...)
 
 			logs := recorded.All()
 			assert.Equal(t, tt.expectedLogs, len(logs))
@@ -172,3 +172,10 @@ func TestUsingDefaultLogger(t *testing.T) {
 	Logf(zapcore.InfoLevel, "test %s", "message")
 	Logln(zapcore.InfoLevel, "test message")
 }
+
+func TestGetTemplateName(t *testing.T) {
+	assert.Equal(t, "sys", GetTemplateName("[sys] %s"))
+	assert.Equal(t, "db", GetTemplateName("db: %s"))
+	assert.Equal(t, "", GetTemplateName("%s"))
+	assert.Equal(t, "", GetTemplateName(""))
+}
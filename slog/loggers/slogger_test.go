@@ -18,13 +18,13 @@ func TestNewSLogger(t *testing.T) {
 		{
 			name:     "create with template only",
 			template: "test-%s",
-			want:     &SLogger{Template: "test-%s"},
+			want:     &SLogger{Template: "test-%s", Name: "test"},
 		},
 		{
 			name:          "create with template and kv",
 			template:      "test-%s",
 			keysAndValues: []any{"key", "value"},
-			want:          &SLogger{Template: "test-%s", KeysAndValues: []any{"key", "value"}},
+			want:          &SLogger{Template: "test-%s", KeysAndValues: []any{"key", "value"}, Name: "test"},
 		},
 	}
 
@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"slog/loggers"
-	"slog/perf"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"github.com/INT-Game/go-tools/slog/perf"
 )
 
 func TestPerformance(t *testing.T) {
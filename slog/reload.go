@@ -0,0 +1,36 @@
+package slog
+
+import (
+	"context"
+
+	"github.com/spf13/viper"
+)
+
+// ReloadFromViper decodes the LogConfig under key, or the whole config if key is empty, and calls Reload.
+func ReloadFromViper(v *viper.Viper, key string) error {
+	var config LogConfig
+	var err error
+	if key == "" {
+		err = v.Unmarshal(&config)
+	} else {
+		err = v.UnmarshalKey(key, &config)
+	}
+	if err != nil {
+		return err
+	}
+	return Reload(config)
+}
+
+// ReloadCallback returns a callback for gt_loader.LoadConfig that reloads slog from the LogConfig under key
+// on the first load and on every config change. Errors are logged and the current outputs are kept.
+//
+//	gt_loader.LoadConfig(ctx, "config.yaml", slog.ReloadCallback("log"))
+func ReloadCallback(key string) func(ctx context.Context, v *viper.Viper) {
+	return func(ctx context.Context, v *viper.Viper) {
+		if err := ReloadFromViper(v, key); err != nil {
+			CErrorw(ctx, "reload log config failed", "key", key, "error", err)
+			return
+		}
+		CInfow(ctx, "log config reloaded", "key", key)
+	}
+}
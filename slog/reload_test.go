@@ -0,0 +1,139 @@
+package slog
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func readLog(t *testing.T, dir string, file string) string {
+	data, err := os.ReadFile(path.Join(dir, file))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	dirA, dirB := path.Join(dir, "a"), path.Join(dir, "b")
+	ctx := context.Background()
+
+	Init(LogConfig{Dir: dirA, File: true})
+	defer Close()
+	contextLogger := GetContextLogger(ctx)
+	CInfo(ctx, "before reload")
+
+	assert.NoError(t, Reload(LogConfig{Dir: dirB, File: true, Level: int(zapcore.DebugLevel)}))
+	assert.Equal(t, zapcore.DebugLevel, GetLevel())
+	CInfo(ctx, "after reload")
+	CDebug(ctx, "debug after reload")
+	// loggers created before the reload write to the new outputs
+	contextLogger.Info("held logger")
+
+	outputA := readLog(t, dirA, OutputLogFile)
+	assert.Contains(t, outputA, "before reload")
+	assert.NotContains(t, outputA, "after reload")
+	outputB := readLog(t, dirB, OutputLogFile)
+	assert.Contains(t, outputB, "after reload")
+	assert.Contains(t, outputB, "held logger")
+	assert.Contains(t, readLog(t, dirB, DebugLogFile), "debug after reload")
+
+	t.Run("invalid config keeps outputs", func(t *testing.T) {
+		file := path.Join(dir, "file")
+		assert.NoError(t, os.WriteFile(file, nil, 0644))
+		assert.Error(t, Reload(LogConfig{Dir: path.Join(file, "sub"), File: true}))
+		CInfo(ctx, "still working")
+		assert.Contains(t, readLog(t, dirB, OutputLogFile), "still working")
+	})
+}
+
+func TestReload_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true})
+	defer Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger := GetContextLogger(context.Background()).With("worker", "w")
+			for j := 0; j < 200; j++ {
+				CInfo(context.Background(), "concurrent")
+				logger.Info("concurrent with fields")
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, Reload(LogConfig{Dir: dir, File: true, RotateConfig: &RotateConfig{MaxSize: i + 1}}))
+	}
+	wg.Wait()
+
+	// no line is lost or interleaved across reloads
+	lines := strings.Split(strings.TrimSpace(readLog(t, dir, OutputLogFile)), "\n")
+	assert.Len(t, lines, 4*200*2)
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}"), "%q", line)
+	}
+}
+
+func TestReloadCallback(t *testing.T) {
+	dir := t.TempDir()
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(`
+log:
+  dir: `+dir+`
+  file: true
+  level: 1
+  logger_levels:
+    sys: -1
+`)))
+
+	ReloadCallback("log")(context.Background(), v)
+	defer Close()
+	assert.Equal(t, zapcore.WarnLevel, GetLevel())
+	assert.Equal(t, map[string]zapcore.Level{"sys": zapcore.DebugLevel}, GetLoggerLevels())
+
+	NewSLogger("[sys] %s").CDebug(context.Background(), "sys debug")
+	CInfo(context.Background(), "hidden info")
+	assert.Contains(t, readLog(t, dir, DebugLogFile), "[sys] sys debug")
+	assert.NotContains(t, readLog(t, dir, OutputLogFile), "hidden info")
+
+	SetLevel(zapcore.InfoLevel)
+	UnsetLoggerLevel("sys")
+}
+
+func TestReload_KeepsRuntimeLevel(t *testing.T) {
+	config := LogConfig{Dir: t.TempDir(), File: true, LoggerLevels: map[string]int{"sys": int(zapcore.WarnLevel)}}
+	Init(config)
+	defer Close()
+
+	// runtime changes survive a reload of the same levels, e.g. by the gt_loader callback
+	SetLevel(zapcore.DebugLevel)
+	SetLoggerLevel("net", zapcore.ErrorLevel)
+	assert.NoError(t, Reload(config))
+	assert.Equal(t, zapcore.DebugLevel, GetLevel())
+	assert.Equal(t, map[string]zapcore.Level{"sys": zapcore.WarnLevel, "net": zapcore.ErrorLevel}, GetLoggerLevels())
+
+	// a changed config level replaces them
+	config.Level = int(zapcore.WarnLevel)
+	config.LoggerLevels = map[string]int{"sys": int(zapcore.InfoLevel)}
+	assert.NoError(t, Reload(config))
+	assert.Equal(t, zapcore.WarnLevel, GetLevel())
+	assert.Equal(t, map[string]zapcore.Level{"sys": zapcore.InfoLevel}, GetLoggerLevels())
+
+	// Init after Close applies the config again
+	SetLevel(zapcore.DebugLevel)
+	Close()
+	Init(config)
+	assert.Equal(t, zapcore.WarnLevel, GetLevel())
+}
@@ -0,0 +1,228 @@
+package slog
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type SamplingRule struct {
+	First      int `mapstructure:"first"`      // entries logged per message key in each interval, negative disables sampling
+	Thereafter int `mapstructure:"thereafter"` // after First, log every Mth entry, 0 drops the rest of the interval
+}
+
+type SamplingConfig struct {
+	Enable          bool                     `mapstructure:"enable"`
+	Interval        time.Duration            `mapstructure:"interval"` // defaults to 1s
+	SamplingRule    `mapstructure:",squash"` // rule of every level, First defaults to 100
+	Levels          map[string]SamplingRule  `mapstructure:"levels"`           // rule by level name, e.g. {"error": {"first": -1}}
+	SummaryInterval time.Duration            `mapstructure:"summary_interval"` // interval of the suppressed entries summary, defaults to 1m
+}
+
+// SummaryMessage is the message of the summary lines reporting suppressed entries.
+const SummaryMessage = "log entries suppressed by sampling"
+
+// samplingKey identifies repeated entries by level and message key: the template of formatted entries
+// (see loggers.TemplateKey), so "recv packet %d" entries share a key whatever the args, otherwise the message.
+type samplingKey struct {
+	level   zapcore.Level
+	message string
+}
+
+func newSamplingKey(entry zapcore.Entry, fields []zapcore.Field) samplingKey {
+	for _, field := range fields {
+		if field.Key == loggers.TemplateKey && field.Type == zapcore.SkipType {
+			return samplingKey{level: entry.Level, message: field.String}
+		}
+	}
+	return samplingKey{level: entry.Level, message: entry.Message}
+}
+
+// samplingCore samples the entries of base by level and message key and reports the dropped entries periodically.
+// The decision is made in Write, where the template field of the entry is known.
+type samplingCore struct {
+	zapcore.Core
+	rules   map[zapcore.Level]SamplingRule
+	state   *samplingState
+	summary *samplingSummary
+}
+
+// samplingState counts the entries by key in the current interval, shared by the cores derived by With.
+type samplingState struct {
+	interval    time.Duration
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[samplingKey]int
+}
+
+type samplingSuppressed struct {
+	message string // first suppressed message
+	count   int
+}
+
+type samplingSummary struct {
+	output     zapcore.Core
+	name       string
+	mu         sync.Mutex
+	suppressed map[samplingKey]*samplingSuppressed
+	done       chan struct{}
+	stopped    chan struct{}
+}
+
+// newSamplingCore wraps base with the sampling config. The summary lines are written to output,
+// so that they follow the runtime level and reloads like any other entry.
+// The returned close function stops the summary and writes the last one.
+func newSamplingCore(base zapcore.Core, output zapcore.Core, config SamplingConfig, name string) (zapcore.Core, func() (err error)) {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	if config.First == 0 {
+		config.First = 100
+	}
+	if config.SummaryInterval <= 0 {
+		config.SummaryInterval = time.Minute
+	}
+	if name == "" {
+		name = DefaultLoggerName
+	}
+
+	summary := &samplingSummary{
+		output:     output,
+		name:       name,
+		suppressed: map[samplingKey]*samplingSuppressed{},
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	core := &samplingCore{
+		Core:    base,
+		rules:   map[zapcore.Level]SamplingRule{},
+		state:   &samplingState{interval: config.Interval, counts: map[samplingKey]int{}},
+		summary: summary,
+	}
+	// dpanic, panic and fatal entries are never sampled
+	for level := zapcore.DebugLevel; level <= zapcore.ErrorLevel; level++ {
+		rule := config.SamplingRule
+		if levelRule, ok := config.Levels[level.String()]; ok {
+			rule = levelRule
+		}
+		if rule.First >= 0 {
+			core.rules[level] = rule
+		}
+	}
+
+	go summary.run(config.SummaryInterval)
+	return core, summary.close
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), rules: c.rules, state: c.state, summary: c.summary}
+}
+
+func (c *samplingCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// summary lines are never sampled
+	if _, ok := c.rules[entry.Level]; !ok || entry.Message == SummaryMessage {
+		return c.Core.Check(entry, ce)
+	}
+	if !c.Core.Enabled(entry.Level) {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+func (c *samplingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	key := newSamplingKey(entry, fields)
+	if !c.state.allow(key, c.rules[entry.Level], entry.Time) {
+		c.summary.add(key, entry.Message)
+		return nil
+	}
+	// write to the outputs of base enabled for the level, as base.Check would have selected
+	if ce := c.Core.Check(entry, nil); ce != nil {
+		ce.ErrorOutput = errorOutput
+		ce.Write(fields...)
+	}
+	return nil
+}
+
+// allow logs the first entries of every key in an interval, then every Thereafter-th entry.
+func (s *samplingState) allow(key samplingKey, rule SamplingRule, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.windowStart) >= s.interval {
+		clear(s.counts)
+		s.windowStart = now
+	}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if n <= rule.First {
+		return true
+	}
+	return rule.Thereafter > 0 && (n-rule.First)%rule.Thereafter == 0
+}
+
+func (s *samplingSummary) add(key samplingKey, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if suppressed, ok := s.suppressed[key]; ok {
+		suppressed.count++
+		return
+	}
+	s.suppressed[key] = &samplingSuppressed{message: message, count: 1}
+}
+
+func (s *samplingSummary) run(interval time.Duration) {
+	defer close(s.stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.write()
+		case <-s.done:
+			s.write()
+			return
+		}
+	}
+}
+
+// write logs one warn line per suppressed level and message key.
+func (s *samplingSummary) write() {
+	s.mu.Lock()
+	suppressed := s.suppressed
+	s.suppressed = map[samplingKey]*samplingSuppressed{}
+	s.mu.Unlock()
+
+	keys := make([]samplingKey, 0, len(suppressed))
+	for key := range suppressed {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].message < keys[j].message
+	})
+	for _, key := range keys {
+		entry := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), LoggerName: s.name, Message: SummaryMessage}
+		if ce := s.output.Check(entry, nil); ce != nil {
+			fields := []zapcore.Field{
+				zap.String("suppressed_level", key.level.String()),
+				zap.String("suppressed_msg", suppressed[key].message),
+				zap.Int("suppressed", suppressed[key].count),
+			}
+			if key.message != suppressed[key].message {
+				fields = append(fields, zap.String("suppressed_template", key.message))
+			}
+			ce.Write(fields...)
+		}
+	}
+}
+
+func (s *samplingSummary) close() (err error) {
+	close(s.done)
+	<-s.stopped
+	return nil
+}
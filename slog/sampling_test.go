@@ -0,0 +1,190 @@
+package slog
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func readLogEntries(t *testing.T, dir string, file string) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(readLog(t, dir, file)), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func countMessage(entries []map[string]any, msg string) int {
+	count := 0
+	for _, entry := range entries {
+		if entry["msg"] == msg {
+			count++
+		}
+	}
+	return count
+}
+
+func TestInit_Sampling(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true, Sampling: &SamplingConfig{
+		Enable:          true,
+		Interval:        time.Hour,
+		SamplingRule:    SamplingRule{First: 2},
+		Levels:          map[string]SamplingRule{"error": {First: -1}},
+		SummaryInterval: time.Hour,
+	}})
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		CInfo(ctx, "repeated")
+		CError(ctx, "error not sampled")
+	}
+	NewSLogger("[sys] %s").CInfo(ctx, "other")
+	Close()
+
+	entries := readLogEntries(t, dir, OutputLogFile)
+	assert.Equal(t, 2, countMessage(entries, "repeated"))
+	assert.Equal(t, 10, countMessage(readLogEntries(t, dir, ErrorLogFile), "error not sampled"))
+	assert.Equal(t, 1, countMessage(entries, "[sys] other"))
+
+	// the summary is written on Close
+	var summaries []map[string]any
+	for _, entry := range entries {
+		if entry["msg"] == SummaryMessage {
+			summaries = append(summaries, entry)
+		}
+	}
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, "warn", summaries[0]["lv"])
+		assert.Equal(t, "info", summaries[0]["suppressed_level"])
+		assert.Equal(t, "repeated", summaries[0]["suppressed_msg"])
+		assert.Equal(t, float64(8), summaries[0]["suppressed"])
+	}
+}
+
+func TestSampling_SummaryInterval(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true, Sampling: &SamplingConfig{
+		Enable:          true,
+		Interval:        time.Hour,
+		SamplingRule:    SamplingRule{First: 1},
+		SummaryInterval: 20 * time.Millisecond,
+	}})
+	defer Close()
+
+	for i := 0; i < 5; i++ {
+		CWarn(context.Background(), "periodic")
+	}
+	assert.Eventually(t, func() bool {
+		return countMessage(readLogEntries(t, dir, OutputLogFile), SummaryMessage) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSampling_MessageKey(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true, Sampling: &SamplingConfig{
+		Enable:          true,
+		Interval:        time.Hour,
+		SamplingRule:    SamplingRule{First: 3},
+		SummaryInterval: time.Hour,
+	}})
+
+	ctx := context.Background()
+	logRequest := func(path string) {
+		// one call site logging different messages, e.g. a shared request logger
+		CInfow(ctx, "GET "+path)
+	}
+	for i := 0; i < 10; i++ {
+		// every message differs, they are sampled by template
+		CInfo(ctx, "recv packet %d", i)
+		logRequest("/a")
+	}
+	CInfo(ctx, "recv packet %d", 10)
+	CInfo(ctx, "send packet %d", 0)
+	logRequest("/b")
+	Close()
+
+	entries := readLogEntries(t, dir, OutputLogFile)
+	count := func(prefix string) int {
+		n := 0
+		for _, entry := range entries {
+			if strings.HasPrefix(entry["msg"].(string), prefix) {
+				n++
+			}
+		}
+		return n
+	}
+	assert.Equal(t, 3, count("recv packet"))
+	assert.Equal(t, 1, count("send packet"))
+	assert.Equal(t, 3, count("GET /a"))
+	assert.Equal(t, 1, count("GET /b"))
+
+	var summaries []map[string]any
+	for _, entry := range entries {
+		if entry["msg"] == SummaryMessage {
+			summaries = append(summaries, entry)
+		}
+	}
+	// summaries are sorted by level and key
+	if assert.Len(t, summaries, 2) {
+		assert.Equal(t, "GET /a", summaries[0]["suppressed_msg"])
+		assert.Equal(t, float64(7), summaries[0]["suppressed"])
+		assert.Nil(t, summaries[0]["suppressed_template"])
+		assert.Equal(t, "recv packet 3", summaries[1]["suppressed_msg"])
+		assert.Equal(t, "recv packet %d", summaries[1]["suppressed_template"])
+		assert.Equal(t, float64(8), summaries[1]["suppressed"])
+	}
+	for _, entry := range entries {
+		assert.Nil(t, entry[loggers.TemplateKey])
+	}
+}
+
+func TestSampling_SummaryLevel(t *testing.T) {
+	dir := t.TempDir()
+	Init(LogConfig{Dir: dir, File: true, Sampling: &SamplingConfig{
+		Enable:          true,
+		Interval:        time.Hour,
+		SamplingRule:    SamplingRule{First: 1},
+		SummaryInterval: time.Hour,
+	}})
+	defer SetLevel(GetLevel())
+
+	for i := 0; i < 5; i++ {
+		CInfo(context.Background(), "hidden summary")
+	}
+	// the summary lines follow the runtime level
+	SetLevel(zapcore.ErrorLevel)
+	Close()
+	assert.Equal(t, 0, countMessage(readLogEntries(t, dir, OutputLogFile), SummaryMessage))
+}
+
+func TestSampling_SummaryReload(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+	Init(LogConfig{Dir: oldDir, File: true, Sampling: &SamplingConfig{
+		Enable:          true,
+		Interval:        time.Hour,
+		SamplingRule:    SamplingRule{First: 1},
+		SummaryInterval: time.Hour,
+	}})
+	for i := 0; i < 5; i++ {
+		CInfo(context.Background(), "reloaded")
+	}
+	// the last summary of the old outputs is written to the current ones
+	assert.NoError(t, Reload(LogConfig{Dir: newDir, File: true}))
+	Close()
+	assert.Equal(t, 0, countMessage(readLogEntries(t, oldDir, OutputLogFile), SummaryMessage))
+	assert.Equal(t, 1, countMessage(readLogEntries(t, newDir, OutputLogFile), SummaryMessage))
+}
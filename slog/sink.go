@@ -0,0 +1,494 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/INT-Game/go-tools/slog/loggers"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Types of SinkConfig
+const (
+	SinkSyslog = "syslog" // RFC5424 syslog over udp or tcp
+	SinkTCP    = "tcp"    // one encoded entry per line over tcp
+	SinkHTTP   = "http"   // encoded entries POSTed in batches, one per line
+)
+
+// Encoders of SinkConfig
+const (
+	SinkEncoderJSON    = "json"
+	SinkEncoderConsole = "console"
+)
+
+type SinkConfig struct {
+	Name          string            `mapstructure:"name"`            // key of GetSinkStats, unique, defaults to type://address or the url
+	Type          string            `mapstructure:"type"`            // syslog, tcp or http
+	Network       string            `mapstructure:"network"`         // udp or tcp for syslog, defaults to udp
+	Address       string            `mapstructure:"address"`         // host:port, or the collector url for http
+	MinLevel      string            `mapstructure:"min_level"`       // lowest level sent, defaults to debug, the global level still applies
+	MaxLevel      string            `mapstructure:"max_level"`       // highest level sent, defaults to fatal
+	Encoder       string            `mapstructure:"encoder"`         // json or console, defaults to json
+	Facility      int               `mapstructure:"facility"`        // syslog facility, defaults to 1 (user)
+	Header        map[string]string `mapstructure:"header"`          // extra http headers
+	BufferSize    int               `mapstructure:"buffer_size"`     // max queued entries, entries are dropped when full, defaults to 1024
+	BatchSize     int               `mapstructure:"batch_size"`      // max entries per send, defaults to 100
+	FlushInterval time.Duration     `mapstructure:"flush_interval"`  // max time an entry waits for an http batch, defaults to 1s
+	Timeout       time.Duration     `mapstructure:"timeout"`         // dial, write and request timeout and max wait of Sync, defaults to 5s
+	MaxRetries    int               `mapstructure:"max_retries"`     // retries of a failed send before the entries are dropped, defaults to 3, negative disables
+	RetryDelay    time.Duration     `mapstructure:"retry_delay"`     // first retry delay, doubled on every retry, defaults to 100ms
+	MaxRetryDelay time.Duration     `mapstructure:"max_retry_delay"` // defaults to 5s
+}
+
+// SinkStats is a snapshot of the counters of a sink.
+type SinkStats struct {
+	Queued  int    // entries waiting in the queue
+	Sent    uint64 // entries delivered
+	Dropped uint64 // entries discarded because the queue was full or the retries were exhausted
+}
+
+// sinkTransport delivers encoded entries, it is only used from the goroutine of its sinkWriter.
+type sinkTransport interface {
+	// send returns the number of lines delivered before err
+	send(lines [][]byte) (int, error)
+	close() error
+}
+
+// newSinkCore builds the core of a sink and returns its writer, whose Close sends the queued entries and
+// closes the connection. appName is used by syslog.
+func newSinkCore(config SinkConfig, appName string) (zapcore.Core, *sinkWriter, error) {
+	if config.Address == "" {
+		return nil, nil, fmt.Errorf("sink %s: empty address", config.Type)
+	}
+	minLevel, maxLevel := zapcore.DebugLevel, zapcore.FatalLevel
+	var err error
+	if config.MinLevel != "" {
+		if minLevel, err = zapcore.ParseLevel(config.MinLevel); err != nil {
+			return nil, nil, fmt.Errorf("sink %s: %w", config.Address, err)
+		}
+	}
+	if config.MaxLevel != "" {
+		if maxLevel, err = zapcore.ParseLevel(config.MaxLevel); err != nil {
+			return nil, nil, fmt.Errorf("sink %s: %w", config.Address, err)
+		}
+	}
+
+	var encoder zapcore.Encoder
+	switch config.Encoder {
+	case "", SinkEncoderJSON:
+		encoder = GetJsonEncoder(hostname)
+	case SinkEncoderConsole:
+		encoder = GetConsoleEncoder(hostname)
+	default:
+		return nil, nil, fmt.Errorf("sink %s: unknown encoder %q", config.Address, config.Encoder)
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	var transport sinkTransport
+	switch config.Type {
+	case SinkSyslog:
+		if config.Network == "" {
+			config.Network = "udp"
+		}
+		if config.Network != "udp" && config.Network != "tcp" {
+			return nil, nil, fmt.Errorf("sink %s: unknown syslog network %q", config.Address, config.Network)
+		}
+		if config.Facility == 0 {
+			config.Facility = 1
+		}
+		encoder = newSyslogEncoder(encoder, config.Facility, appName, config.Network == "tcp")
+		transport = &connTransport{network: config.Network, address: config.Address, timeout: config.Timeout}
+	case SinkTCP:
+		transport = &connTransport{network: "tcp", address: config.Address, timeout: config.Timeout}
+	case SinkHTTP:
+		if _, err = url.ParseRequestURI(config.Address); err != nil {
+			return nil, nil, fmt.Errorf("sink %s: %w", config.Address, err)
+		}
+		transport = &httpTransport{url: config.Address, header: config.Header, client: &http.Client{Timeout: config.Timeout}}
+	default:
+		return nil, nil, fmt.Errorf("sink %s: unknown type %q", config.Address, config.Type)
+	}
+	w := newSinkWriter(sinkName(config), config, transport)
+	enabler := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+		return minLevel <= lvl && lvl <= maxLevel
+	})
+	return zapcore.NewCore(encoder, w, enabler), w, nil
+}
+
+// sinkName returns the key of the sink in GetSinkStats.
+func sinkName(config SinkConfig) string {
+	switch {
+	case config.Name != "":
+		return config.Name
+	case config.Type == SinkHTTP:
+		return config.Address
+	default:
+		return config.Type + "://" + config.Address
+	}
+}
+
+// GetSinkStats returns the counters of the current sinks by name.
+func GetSinkStats() map[string]SinkStats {
+	stats := map[string]SinkStats{}
+	if out := outputs.Load(); out != nil {
+		for name, w := range out.sinks {
+			stats[name] = w.Stats()
+		}
+	}
+	return stats
+}
+
+// sinkWriter is a zapcore.WriteSyncer queueing entries and sending them from a single goroutine,
+// so a slow or unreachable collector never blocks logging.
+type sinkWriter struct {
+	name      string
+	config    SinkConfig
+	transport sinkTransport
+	batched   bool
+	queue     chan []byte
+	sent      atomic.Uint64
+	dropped   atomic.Uint64
+
+	mu      sync.RWMutex
+	closed  bool
+	syncReq chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newSinkWriter(name string, config SinkConfig, transport sinkTransport) *sinkWriter {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1024
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 100 * time.Millisecond
+	}
+	if config.MaxRetryDelay <= 0 {
+		config.MaxRetryDelay = 5 * time.Second
+	}
+	w := &sinkWriter{
+		name:      name,
+		config:    config,
+		transport: transport,
+		batched:   config.Type == SinkHTTP,
+		queue:     make(chan []byte, config.BufferSize),
+		syncReq:   make(chan chan struct{}),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, or drops it if the queue is full.
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	line := append([]byte(nil), p...)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	select {
+	case w.queue <- line:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Sync makes a single attempt to send the queued entries and waits for it at most the configured Timeout.
+// It never retries: zap syncs dpanic, panic and fatal entries while the root lock is held.
+func (w *sinkWriter) Sync() error {
+	timer := time.NewTimer(w.config.Timeout)
+	defer timer.Stop()
+	reply := make(chan struct{})
+	select {
+	case w.syncReq <- reply:
+	case <-w.stopped:
+		return nil
+	case <-timer.C:
+		return nil
+	}
+	select {
+	case <-reply:
+	case <-timer.C:
+	}
+	return nil
+}
+
+// Close sends the queued entries with a single attempt each and closes the transport.
+func (w *sinkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+	<-w.stopped
+	return nil
+}
+
+func (w *sinkWriter) Stats() SinkStats {
+	return SinkStats{Queued: len(w.queue), Sent: w.sent.Load(), Dropped: w.dropped.Load()}
+}
+
+func (w *sinkWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+	batch := make([][]byte, 0, w.config.BatchSize)
+	for {
+		select {
+		case line := <-w.queue:
+			batch = append(batch, line)
+			// stream transports send right away, http waits for a full batch or the ticker
+			if !w.batched || len(batch) >= w.config.BatchSize {
+				batch, _ = w.send(w.collect(batch), true)
+			}
+		case <-ticker.C:
+			batch, _ = w.send(batch, true)
+		case reply := <-w.syncReq:
+			batch = w.flush(batch)
+			close(reply)
+		case <-w.done:
+			w.drain(batch)
+			if err := w.transport.close(); err != nil {
+				loggers.DefaultErrorf("close log sink %s failed: %v", w.config.Address, err)
+			}
+			return
+		}
+	}
+}
+
+// collect appends the queued entries to batch without waiting, up to the batch size.
+func (w *sinkWriter) collect(batch [][]byte) [][]byte {
+	for len(batch) < w.config.BatchSize {
+		select {
+		case line := <-w.queue:
+			batch = append(batch, line)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// drain sends batch and every queued entry.
+func (w *sinkWriter) drain(batch [][]byte) [][]byte {
+	for {
+		batch, _ = w.send(w.collect(batch), true)
+		if len(w.queue) == 0 {
+			return batch
+		}
+	}
+}
+
+// flush sends batch and the queued entries with a single attempt each. It stops at the first failure,
+// whose entries are dropped, and leaves the rest queued.
+func (w *sinkWriter) flush(batch [][]byte) [][]byte {
+	for {
+		var err error
+		batch, err = w.send(w.collect(batch), false)
+		if err != nil || len(w.queue) == 0 {
+			return batch
+		}
+	}
+}
+
+// send delivers batch, with exponential backoff if retry is set, and returns it emptied for reuse
+// with the error of the last attempt.
+func (w *sinkWriter) send(batch [][]byte, retry bool) ([][]byte, error) {
+	lines := batch
+	delay := w.config.RetryDelay
+	var err error
+	for attempt := 0; len(lines) > 0; attempt++ {
+		var n int
+		n, err = w.transport.send(lines)
+		w.sent.Add(uint64(n))
+		lines = lines[n:]
+		if err == nil {
+			continue
+		}
+		if !retry || attempt >= w.config.MaxRetries || !w.wait(delay) {
+			w.dropped.Add(uint64(len(lines)))
+			loggers.DefaultErrorf("log sink %s dropped %d entries: %v", w.config.Address, len(lines), err)
+			break
+		}
+		delay = min(delay*2, w.config.MaxRetryDelay)
+	}
+	clear(batch)
+	return batch[:0], err
+}
+
+// wait sleeps for delay, it returns false at once if the writer is closed.
+func (w *sinkWriter) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// connTransport writes every line to a udp or tcp connection, reconnecting after a failure.
+type connTransport struct {
+	network string
+	address string
+	timeout time.Duration
+	conn    net.Conn
+}
+
+func (t *connTransport) send(lines [][]byte) (int, error) {
+	if t.conn == nil {
+		conn, err := net.DialTimeout(t.network, t.address, t.timeout)
+		if err != nil {
+			return 0, err
+		}
+		t.conn = conn
+	}
+	for i, line := range lines {
+		_ = t.conn.SetWriteDeadline(time.Now().Add(t.timeout))
+		if _, err := t.conn.Write(line); err != nil {
+			_ = t.conn.Close()
+			t.conn = nil
+			return i, err
+		}
+	}
+	return len(lines), nil
+}
+
+func (t *connTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// httpTransport POSTs the lines of a batch as one newline delimited body.
+type httpTransport struct {
+	url    string
+	header map[string]string
+	client *http.Client
+}
+
+func (t *httpTransport) send(lines [][]byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(bytes.Join(lines, nil)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for key, value := range t.header {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return len(lines), nil
+}
+
+func (t *httpTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+var syslogPool = buffer.NewPool()
+
+// syslogEncoder wraps the encoded entry as the MSG of a RFC5424 message,
+// framed by octet counting (RFC6587) over tcp.
+type syslogEncoder struct {
+	zapcore.Encoder
+	facility      int
+	appName       string
+	procID        int
+	octetCounting bool
+}
+
+func newSyslogEncoder(encoder zapcore.Encoder, facility int, appName string, octetCounting bool) *syslogEncoder {
+	return &syslogEncoder{Encoder: encoder, facility: facility, appName: appName, procID: os.Getpid(), octetCounting: octetCounting}
+}
+
+func (e *syslogEncoder) Clone() zapcore.Encoder {
+	clone := *e
+	clone.Encoder = e.Encoder.Clone()
+	return &clone
+}
+
+func (e *syslogEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	msg, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer msg.Free()
+
+	host := hostname
+	if host == "" {
+		host = "-"
+	}
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	header := fmt.Sprintf("<%d>1 %s %s %s %d - - ",
+		e.facility*8+syslogSeverity(entry.Level), entry.Time.Format("2006-01-02T15:04:05.000000Z07:00"), host, e.appName, e.procID)
+	body := bytes.TrimRight(msg.Bytes(), "\n")
+
+	buf := syslogPool.Get()
+	if e.octetCounting {
+		buf.AppendInt(int64(len(header) + len(body)))
+		buf.AppendByte(' ')
+	}
+	buf.AppendString(header)
+	_, _ = buf.Write(body)
+	return buf, nil
+}
+
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	case zapcore.FatalLevel:
+		return 0
+	}
+	return 7
+}
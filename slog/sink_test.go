@@ -0,0 +1,242 @@
+package slog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// listenTCP accepts connections on a local port and sends every received line to the returned channel.
+func listenTCP(t *testing.T, split bufio.SplitFunc) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	lines := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				scanner.Split(split)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String(), lines
+}
+
+func receive(t *testing.T, lines <-chan string) string {
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("no line received")
+		return ""
+	}
+}
+
+// splitOctetCounting splits RFC6587 octet counted frames.
+func splitOctetCounting(data []byte, atEOF bool) (int, []byte, error) {
+	space := strings.IndexByte(string(data), ' ')
+	if space < 0 {
+		return 0, nil, nil
+	}
+	length, err := strconv.Atoi(string(data[:space]))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < space+1+length {
+		return 0, nil, nil
+	}
+	return space + 1 + length, data[space+1 : space+1+length], nil
+}
+
+func TestSink_TCP(t *testing.T) {
+	address, lines := listenTCP(t, bufio.ScanLines)
+	Init(LogConfig{Sinks: []SinkConfig{{Type: SinkTCP, Address: address, MinLevel: "warn", MaxLevel: "error"}}})
+	defer Close()
+
+	ctx := context.Background()
+	CInfo(ctx, "info hidden")
+	CWarnw(ctx, "tcp warn", "uid", 1)
+	CError(ctx, "tcp error")
+
+	for _, msg := range []string{"tcp warn", "tcp error"} {
+		entry := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(receive(t, lines)), &entry))
+		assert.Equal(t, msg, entry["msg"])
+	}
+	assert.Eventually(t, func() bool {
+		return GetSinkStats()["tcp://"+address] == SinkStats{Sent: 2}
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSink_Syslog(t *testing.T) {
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		Init(LogConfig{Name: "svc", Sinks: []SinkConfig{{Type: SinkSyslog, Address: conn.LocalAddr().String(), Encoder: SinkEncoderConsole}}})
+		defer Close()
+		CWarn(context.Background(), "syslog warn")
+
+		buf := make([]byte, 4096)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := string(buf[:n])
+		// facility user (1) * 8 + severity warning (4)
+		assert.True(t, strings.HasPrefix(msg, "<12>1 "), msg)
+		assert.Contains(t, msg, " svc ")
+		assert.Contains(t, msg, " [WARN] svc@"+hostname+" ")
+		assert.True(t, strings.HasSuffix(msg, "syslog warn"), msg)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		address, lines := listenTCP(t, splitOctetCounting)
+		Init(LogConfig{Sinks: []SinkConfig{{Type: SinkSyslog, Network: "tcp", Address: address, Facility: 16}}})
+		defer Close()
+		CInfo(context.Background(), "first")
+		CWarn(context.Background(), "second")
+
+		first, second := receive(t, lines), receive(t, lines)
+		assert.True(t, strings.HasPrefix(first, "<134>1 "), first)
+		assert.Contains(t, first, " "+DefaultLoggerName+" ")
+		assert.Contains(t, first, `"msg":"first"`)
+		assert.True(t, strings.HasPrefix(second, "<132>1 "), second)
+	})
+}
+
+func TestSink_HTTP(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, "token", r.Header.Get("X-Token"))
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		batches = append(batches, strings.Split(strings.TrimSpace(string(body)), "\n"))
+	}))
+	defer server.Close()
+
+	Init(LogConfig{Sinks: []SinkConfig{{
+		Name:          "collector",
+		Type:          SinkHTTP,
+		Address:       server.URL,
+		Header:        map[string]string{"X-Token": "token"},
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+		RetryDelay:    10 * time.Millisecond,
+	}}})
+	ctx := context.Background()
+	for i := 0; i < 4; i++ {
+		CInfo(ctx, "batched %d", i)
+	}
+	// the full batch is retried after the 503, the last entry waits for Close
+	assert.Eventually(t, func() bool { return GetSinkStats()["collector"].Sent == 3 }, time.Second, 5*time.Millisecond)
+	Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, requests)
+	if assert.Len(t, batches, 2) {
+		assert.Len(t, batches[0], 3)
+		assert.Contains(t, batches[0][2], "batched 2")
+		assert.Len(t, batches[1], 1)
+		assert.Contains(t, batches[1][0], "batched 3")
+	}
+	assert.Empty(t, GetSinkStats())
+}
+
+func TestSink_Unreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	Init(LogConfig{Sinks: []SinkConfig{{Type: SinkTCP, Address: address, MaxRetries: -1, BufferSize: 1}}})
+	defer Close()
+	for i := 0; i < 10; i++ {
+		CInfo(context.Background(), "lost")
+	}
+	assert.NoError(t, ZapLogger.Sync())
+	stats := GetSinkStats()["tcp://"+address]
+	assert.Equal(t, uint64(0), stats.Sent)
+	assert.Equal(t, uint64(10), stats.Dropped)
+}
+
+func TestSink_SyncTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	Init(LogConfig{Sinks: []SinkConfig{{Type: SinkTCP, Address: address, Timeout: 50 * time.Millisecond, MaxRetries: 10, RetryDelay: time.Second}}})
+	defer Close()
+	// zap syncs the outputs after a dpanic entry while the writers are locked
+	start := time.Now()
+	CDPanic(context.Background(), "collector down")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// a reload is not blocked by the retries either
+	start = time.Now()
+	assert.NoError(t, Reload(LogConfig{}))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestReload_InvalidSink(t *testing.T) {
+	for _, sink := range []SinkConfig{
+		{Type: "kafka", Address: "127.0.0.1:9092"},
+		{Type: SinkTCP},
+		{Type: SinkTCP, Address: "127.0.0.1:1", MinLevel: "verbose"},
+		{Type: SinkSyslog, Address: "127.0.0.1:514", Network: "unix"},
+		{Type: SinkHTTP, Address: "collector"},
+	} {
+		assert.Error(t, Reload(LogConfig{Sinks: []SinkConfig{sink}}), "%+v", sink)
+	}
+
+	duplicate := SinkConfig{Type: SinkTCP, Address: "127.0.0.1:1"}
+	assert.Error(t, Reload(LogConfig{Sinks: []SinkConfig{duplicate, duplicate}}))
+	named := duplicate
+	named.Name = "backup"
+	assert.NoError(t, Reload(LogConfig{Sinks: []SinkConfig{duplicate, named}}))
+	assert.Len(t, GetSinkStats(), 2)
+	Close()
+	assert.Empty(t, GetSinkStats())
+}
@@ -2,9 +2,13 @@ package slog
 
 import (
 	"context"
+	"fmt"
 	"github.com/INT-Game/go-tools/slog/log_context"
 	"github.com/INT-Game/go-tools/slog/loggers"
+	"maps"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,14 +17,66 @@ import (
 var Logger *zap.SugaredLogger
 var ZapLogger *zap.Logger
 
-var debugCloseFunc func() (err error) = func() (err error) { return }
-var outputCloseFunc func() (err error) = func() (err error) { return }
-var errorCloseFunc func() (err error) = func() (err error) { return }
-var fileDebugSyncer zapcore.WriteSyncer
-var fileOutputSyncer zapcore.WriteSyncer
-var fileErrorSyncer zapcore.WriteSyncer
+var root *swapRoot
+var reloadMu sync.Mutex
 
+// levels of the last applied config. Reload keeps the changes made at runtime by SetLevel,
+// SetLoggerLevel or LevelHandler until the levels of the config change.
+var configLevel *zapcore.Level
+var configLoggerLevels map[string]zapcore.Level
+
+// coreOutputs holds the writers of a core built by newCore.
+type coreOutputs struct {
+	closers      []func() (err error)    // release the file writers and sinks
+	closeSummary func() (err error)      // stops the sampling summary, nil without sampling
+	async        map[string]*AsyncWriter // by log file name
+	sinks        map[string]*sinkWriter  // by sink name
+}
+
+// outputs of the current core, published only once the core is committed so that
+// GetAsyncStats and GetSinkStats never report the writers of a failed Reload.
+var outputs atomic.Pointer[coreOutputs]
+
+// Init initializes the global loggers. Calling it again behaves like Reload.
 func Init(config LogConfig) {
+	if err := Reload(config); err != nil {
+		panic(err)
+	}
+}
+
+// Reload atomically replaces the outputs, rotate configs and levels with config.
+// Loggers created before, e.g. by gin_logger or GetContextLogger, switch to the new outputs as well.
+// The old file writers are closed once no write can reach them. On error the current outputs are kept.
+// Levels changed at runtime are kept unless the levels of config differ from the previous ones.
+func Reload(config LogConfig) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if root == nil {
+		root = newSwapRoot(zapcore.NewNopCore())
+	}
+	core, out, err := newCore(&config)
+	if err != nil {
+		return err
+	}
+
+	oldCore := root.swap(core)
+	setLoggers(config)
+	if old := outputs.Swap(out); old != nil {
+		// the last summary of the old core is written to the new one
+		closeCore(oldCore, append([]func() (err error){old.closeSummary}, old.closers...))
+	}
+	return nil
+}
+
+// newCore builds the tee of console, file and sink outputs described by config and returns its writers.
+func newCore(config *LogConfig) (core zapcore.Core, out *coreOutputs, err error) {
+	if config.Async != nil && config.Async.Enable {
+		if err = checkAsyncPolicy(config.Async.Policy); err != nil {
+			return nil, nil, err
+		}
+	}
+	out = &coreOutputs{async: map[string]*AsyncWriter{}, sinks: map[string]*sinkWriter{}}
+
 	// Config stderr and stdout files
 	priorityDebug := zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 		return lvl <= zapcore.DebugLevel
@@ -37,22 +93,28 @@ func Init(config LogConfig) {
 	consoleStdout := zapcore.Lock(os.Stdout)
 	consoleStderr := zapcore.Lock(os.Stderr)
 
-	err := os.MkdirAll(config.Dir, os.ModePerm)
-	if err != nil {
-		panic(err)
-	}
-
-	// init rotate config & write syncer
-	debugConfig, outputConfig, errorConfig := GetRotateConfigs(&config)
-	fileDebugSyncer, debugCloseFunc = GetFileSyncer(debugConfig, config.Dir, DebugLogFile)
-	fileOutputSyncer, outputCloseFunc = GetFileSyncer(outputConfig, config.Dir, OutputLogFile)
-	fileErrorSyncer, errorCloseFunc = GetFileSyncer(errorConfig, config.Dir, ErrorLogFile)
-
 	// Get encoders and their configs
 	jsonEncoder, consoleEncoder := InitEncoders()
 
 	zapcores := []zapcore.Core{}
 	if config.File {
+		err = os.MkdirAll(config.Dir, os.ModePerm)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// init rotate config & write syncer
+		debugConfig, outputConfig, errorConfig := GetRotateConfigs(config)
+		fileDebugSyncer, debugCloseFunc := GetFileSyncer(debugConfig, config.Dir, DebugLogFile)
+		fileOutputSyncer, outputCloseFunc := GetFileSyncer(outputConfig, config.Dir, OutputLogFile)
+		fileErrorSyncer, errorCloseFunc := GetFileSyncer(errorConfig, config.Dir, ErrorLogFile)
+		if config.Async != nil && config.Async.Enable {
+			fileDebugSyncer, debugCloseFunc = out.newAsyncFileSyncer(*config.Async, DebugLogFile, fileDebugSyncer, debugCloseFunc)
+			fileOutputSyncer, outputCloseFunc = out.newAsyncFileSyncer(*config.Async, OutputLogFile, fileOutputSyncer, outputCloseFunc)
+			fileErrorSyncer, errorCloseFunc = out.newAsyncFileSyncer(*config.Async, ErrorLogFile, fileErrorSyncer, errorCloseFunc)
+		}
+		out.closers = append(out.closers, debugCloseFunc, outputCloseFunc, errorCloseFunc)
+
 		zapcores = append(zapcores, zapcore.NewCore(jsonEncoder, fileDebugSyncer, priorityDebug))
 		zapcores = append(zapcores, zapcore.NewCore(jsonEncoder, fileOutputSyncer, priorityOutput))
 		zapcores = append(zapcores, zapcore.NewCore(jsonEncoder, fileErrorSyncer, priorityError))
@@ -62,17 +124,79 @@ func Init(config LogConfig) {
 		zapcores = append(zapcores, zapcore.NewCore(consoleEncoder, consoleStdout, priorityOutput))
 		zapcores = append(zapcores, zapcore.NewCore(consoleEncoder, consoleStderr, priorityError))
 	}
-	core := zapcore.NewTee(zapcores...)
+	appName := config.Name
+	if appName == "" {
+		appName = DefaultLoggerName
+	}
+	for _, sinkConfig := range config.Sinks {
+		if _, ok := out.sinks[sinkName(sinkConfig)]; ok {
+			closeCore(nil, out.closers)
+			return nil, nil, fmt.Errorf("sink %s: duplicate name, set a unique name", sinkName(sinkConfig))
+		}
+		sinkCore, w, err := newSinkCore(sinkConfig, appName)
+		if err != nil {
+			closeCore(nil, out.closers)
+			return nil, nil, err
+		}
+		out.sinks[w.name] = w
+		out.closers = append(out.closers, w.Close)
+		zapcores = append(zapcores, sinkCore)
+	}
+	core = zapcore.NewTee(zapcores...)
+	if config.Sampling != nil && config.Sampling.Enable {
+		// summary lines go through the root like other entries, so the runtime level applies to them
+		core, out.closeSummary = newSamplingCore(core, loggers.NewLevelCore(newSwapCore(root)), *config.Sampling, config.Name)
+	}
+	return core, out, nil
+}
+
+// setLoggers applies the levels that changed in config and creates the global loggers on top of the shared root.
+// The logger name is only applied when the loggers are created, i.e. by the first Init or Reload after Close.
+func setLoggers(config LogConfig) {
+	level := zapcore.Level(config.Level)
+	if configLevel == nil || *configLevel != level {
+		loggers.Level.SetLevel(level)
+		configLevel = &level
+	}
+	loggerLevels := make(map[string]zapcore.Level, len(config.LoggerLevels))
+	for name, level := range config.LoggerLevels {
+		loggerLevels[name] = zapcore.Level(level)
+	}
+	if configLoggerLevels == nil || !maps.Equal(configLoggerLevels, loggerLevels) {
+		loggers.SetNameLevels(loggerLevels)
+		configLoggerLevels = loggerLevels
+	}
 
+	if ZapLogger != nil {
+		// the global loggers already write through root, reassigning them would race with readers
+		return
+	}
 	if config.Name == "" {
 		config.Name = DefaultLoggerName
 	}
+	core := loggers.NewLevelCore(newSwapCore(root))
 	ZapLogger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel)).Named(config.Name)
 	loggers.Logger_2 = ZapLogger.WithOptions(zap.AddCallerSkip(2)).Sugar()
 	Logger = ZapLogger.Sugar()
 }
 
+func closeCore(core zapcore.Core, closers []func() (err error)) {
+	if core != nil {
+		_ = core.Sync()
+	}
+	for _, closeFunc := range closers {
+		if closeFunc == nil {
+			continue
+		}
+		if err := closeFunc(); err != nil {
+			loggers.DefaultPrintln(err.Error())
+		}
+	}
+}
+
 func Close() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
 	if Logger != nil {
 		err := Logger.Sync()
 		if err != nil {
@@ -83,19 +207,16 @@ func Close() {
 		ZapLogger = nil
 	}
 	loggers.UsingDefaultLogger()
-	err := debugCloseFunc()
-	if err != nil {
-		loggers.DefaultPrintln(err.Error())
-	}
-
-	err = outputCloseFunc()
-	if err != nil {
-		loggers.DefaultPrintln(err.Error())
-	}
-	err = errorCloseFunc()
-	if err != nil {
-		loggers.DefaultPrintln(err.Error())
+	configLevel, configLoggerLevels = nil, nil
+	out := outputs.Swap(nil)
+	if out == nil {
+		return
 	}
+	// write the last summary before the outputs are detached and closed
+	closeCore(nil, []func() (err error){out.closeSummary})
+	// loggers still held by other packages write nowhere after Close
+	root.swap(zapcore.NewNopCore())
+	closeCore(nil, out.closers)
 }
 
 var CLog = loggers.CLog
@@ -122,6 +243,10 @@ var CPanicw = loggers.CPanicw
 var CFatal = loggers.CFatal
 var CFatalln = loggers.CFatalln
 var CFatalw = loggers.CFatalw
+var CLogEvery = loggers.CLogEvery
+var CInfoEvery = loggers.CInfoEvery
+var CWarnEvery = loggers.CWarnEvery
+var CErrorEvery = loggers.CErrorEvery
 var GetLogContext = log_context.GetLogContext
 var SetContextKeyValue = log_context.SetLogContextKeyValue
 